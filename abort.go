@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/gin-gonic/gin"
+)
+
+// abortWriter records whether writing the response failed because the client
+// went away.
+type abortWriter struct {
+	gin.ResponseWriter
+	broken bool
+}
+
+func (w *abortWriter) observe(err error) {
+	if err != nil && (errors.Is(err, syscall.EPIPE) || errors.Is(err, syscall.ECONNRESET)) {
+		w.broken = true
+	}
+}
+
+// Write implements io.Writer.
+func (w *abortWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.observe(err)
+	return n, err
+}
+
+// WriteString implements io.StringWriter.
+func (w *abortWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.observe(err)
+	return n, err
+}
+
+// clientAborted reports whether the client disconnected before the response
+// was complete: the request context was canceled or a write hit a broken pipe.
+func clientAborted(c *gin.Context, w *abortWriter) bool {
+	return errors.Is(c.Request.Context().Err(), context.Canceled) || w.broken
+}
@@ -0,0 +1,183 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// AccessRecord is the typed form of an access log entry. The middleware fills it
+// from the same values as the slog.Record, so sinks feeding column stores or
+// metrics can use typed fields instead of re-parsing attributes.
+type AccessRecord struct {
+	Time          time.Time     `json:"time"`
+	Level         slog.Level    `json:"level"`
+	Message       string        `json:"msg"`
+	Status        int           `json:"status"`
+	Method        string        `json:"method"`
+	Path          string        `json:"path"`
+	Query         string        `json:"query"`
+	URI           string        `json:"uri,omitempty"`
+	Proto         string        `json:"proto,omitempty"`
+	Host          string        `json:"host,omitempty"`
+	Scheme        string        `json:"scheme,omitempty"`
+	Route         string        `json:"route"`
+	GraphQLOp     string        `json:"graphql_operation,omitempty"`
+	GraphQLType   string        `json:"graphql_type,omitempty"`
+	IP            string        `json:"ip"`
+	ForwardedFor  []string      `json:"forwarded_for,omitempty"`
+	Forwarded     *ProxyHop     `json:"forwarded,omitempty"`
+	ProxyChain    []ProxyHop    `json:"proxy_chain,omitempty"`
+	Latency       time.Duration `json:"latency"`
+	LatencyBucket string        `json:"latency_bucket,omitempty"`
+	Slow          bool          `json:"slow,omitempty"`
+	Large         bool          `json:"large_response,omitempty"`
+	TTFB          time.Duration `json:"ttfb,omitempty"`
+	Stream        time.Duration `json:"stream_duration,omitempty"`
+	Referer       string        `json:"referer"`
+	UserAgent     string        `json:"user_agent"`
+	Principal     string        `json:"principal,omitempty"`
+	BodySize      int           `json:"body_size"`
+	RequestSize   int64         `json:"request_size,omitempty"`
+	RequestRead   int64         `json:"request_body_read,omitempty"`
+	RequestType   string        `json:"request_content_type,omitempty"`
+	ResponseType  string        `json:"response_content_type,omitempty"`
+	Hijacked      bool          `json:"hijacked,omitempty"`
+	ClientAborted bool          `json:"client_aborted,omitempty"`
+	ContextError  string        `json:"context_error,omitempty"`
+	Budget        time.Duration `json:"deadline_budget,omitempty"`
+	BudgetLeft    time.Duration `json:"deadline_remaining,omitempty"`
+	TimedOut      bool          `json:"timed_out,omitempty"`
+	RequestID     string        `json:"request_id,omitempty"`
+	ErrorID       string        `json:"error_id,omitempty"`
+	Tenant        string        `json:"tenant,omitempty"`
+	TraceID       string        `json:"trace_id,omitempty"`
+	SpanID        string        `json:"span_id,omitempty"`
+	Errors        []string      `json:"errors,omitempty"`
+	Panic         *slog.Value   `json:"-"`
+	RequestBody   *string       `json:"request_body,omitempty"`
+	ResponseBody  *string       `json:"response_body,omitempty"`
+}
+
+// Map returns the record's non-empty fields keyed by their Field name.
+func (a *AccessRecord) Map() map[string]any {
+	m := map[string]any{
+		string(FieldStatus):    a.Status,
+		string(FieldMethod):    a.Method,
+		string(FieldPath):      a.Path,
+		string(FieldQuery):     a.Query,
+		string(FieldRoute):     a.Route,
+		string(FieldIP):        a.IP,
+		string(FieldLatency):   a.Latency,
+		string(FieldReferer):   a.Referer,
+		string(FieldUserAgent): a.UserAgent,
+		string(FieldBodySize):  a.BodySize,
+	}
+	if a.Proto != "" {
+		m[string(FieldProto)] = a.Proto
+		m[string(FieldHost)] = a.Host
+		m[string(FieldScheme)] = a.Scheme
+	}
+	if len(a.ForwardedFor) > 0 {
+		m[string(FieldForwardedFor)] = a.ForwardedFor
+	}
+	if a.Forwarded != nil {
+		m[string(FieldForwarded)] = *a.Forwarded
+	}
+	if len(a.ProxyChain) > 0 {
+		m[string(FieldProxyChain)] = a.ProxyChain
+	}
+	if a.GraphQLType != "" {
+		m[string(FieldGraphQLOperation)] = a.GraphQLOp
+		m[string(FieldGraphQLType)] = a.GraphQLType
+	}
+	if a.URI != "" {
+		m[string(FieldURI)] = a.URI
+	}
+	if a.LatencyBucket != "" {
+		m[string(FieldLatencyBucket)] = a.LatencyBucket
+	}
+	if a.Slow {
+		m[string(FieldSlow)] = true
+	}
+	if a.Large {
+		m[string(FieldLargeResponse)] = true
+	}
+	if a.RequestSize > 0 {
+		m[string(FieldRequestSize)] = a.RequestSize
+	}
+	if a.RequestRead > 0 {
+		m[string(FieldRequestBodyRead)] = a.RequestRead
+	}
+	if a.RequestType != "" {
+		m[string(FieldRequestContentType)] = a.RequestType
+	}
+	if a.ResponseType != "" {
+		m[string(FieldResponseContentType)] = a.ResponseType
+	}
+	if a.Principal != "" {
+		m[string(FieldPrincipal)] = a.Principal
+	}
+	if a.TTFB > 0 {
+		m[string(FieldTTFB)] = a.TTFB
+		m[string(FieldStreamDuration)] = a.Stream
+	}
+	if a.Hijacked {
+		m[string(FieldHijacked)] = true
+	}
+	if a.ClientAborted {
+		m[string(FieldClientAborted)] = true
+	}
+	if a.ContextError != "" {
+		m[string(FieldContextError)] = a.ContextError
+	}
+	if a.Budget != 0 {
+		m[string(FieldDeadlineBudget)] = a.Budget
+		m[string(FieldDeadlineRemaining)] = a.BudgetLeft
+	}
+	if a.TimedOut {
+		m[string(FieldTimedOut)] = true
+	}
+	if a.RequestID != "" {
+		m[string(FieldRequestID)] = a.RequestID
+	}
+	if a.ErrorID != "" {
+		m[string(FieldErrorID)] = a.ErrorID
+	}
+	if a.Tenant != "" {
+		m[string(FieldTenant)] = a.Tenant
+	}
+	if a.TraceID != "" {
+		m[string(FieldTraceID)] = a.TraceID
+	}
+	if a.SpanID != "" {
+		m[string(FieldSpanID)] = a.SpanID
+	}
+	if len(a.Errors) > 0 {
+		m[string(FieldErrors)] = a.Errors
+	}
+	if a.Panic != nil {
+		m[string(FieldPanic)] = a.Panic.Any()
+	}
+	if a.RequestBody != nil {
+		m[string(FieldRequestBody)] = *a.RequestBody
+	}
+	if a.ResponseBody != nil {
+		m[string(FieldResponseBody)] = *a.ResponseBody
+	}
+	return m
+}
+
+type accessRecordKey struct{}
+
+// AccessRecordFromContext returns the AccessRecord of the entry being handled.
+// The middleware attaches it to the context passed to slog.Handler.Handle, so
+// sink handlers can use the typed record alongside the slog.Record.
+func AccessRecordFromContext(ctx context.Context) (*AccessRecord, bool) {
+	a, ok := ctx.Value(accessRecordKey{}).(*AccessRecord)
+	return a, ok
+}
+
+func contextWithAccessRecord(ctx context.Context, a *AccessRecord) context.Context {
+	return context.WithValue(ctx, accessRecordKey{}, a)
+}
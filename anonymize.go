@@ -0,0 +1,45 @@
+package slog
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/netip"
+)
+
+// anonymizedIPv6Bits is the IPv6 prefix kept by anonymizeIP.
+const anonymizedIPv6Bits = 48
+
+// anonymizeIP zeroes the last octet of an IPv4 address and the last 80 bits of
+// an IPv6 address. Values that are not addresses are returned unchanged.
+func anonymizeIP(ip string) string {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return ip
+	}
+	addr = addr.Unmap()
+	if addr.Is4() {
+		b := addr.As4()
+		b[3] = 0
+		return netip.AddrFrom4(b).String()
+	}
+	return netip.PrefixFrom(addr.WithZone(""), anonymizedIPv6Bits).Masked().Addr().String()
+}
+
+// hashIP returns a salted hash of ip, stable for a given salt.
+func hashIP(salt []byte, ip string) string {
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ip))
+	return hex.EncodeToString(mac.Sum(nil)[:16])
+}
+
+// loggedIP applies the WithAnonymizeIP and WithHashedIP settings to ip.
+func (cfg *config) loggedIP(ip string) string {
+	if cfg.anonymizeIP {
+		ip = anonymizeIP(ip)
+	}
+	if cfg.ipHashSalt != nil {
+		ip = hashIP(cfg.ipHashSalt, ip)
+	}
+	return ip
+}
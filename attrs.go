@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const attrsKey = "_gin-contrib/slog/attrs_"
+
+/*
+AddAttrs attaches attributes to the request's access log record, so handlers
+can record values such as user_id or cache_hit while processing the request:
+
+	slog.AddAttrs(c, slog.String("user_id", id), slog.Bool("cache_hit", hit))
+
+AddAttrs is not safe for concurrent use on the same gin.Context.
+*/
+func AddAttrs(c *gin.Context, attrs ...slog.Attr) {
+	if len(attrs) == 0 {
+		return
+	}
+	v, _ := c.Get(attrsKey)
+	existing, _ := v.([]slog.Attr)
+	c.Set(attrsKey, append(existing, attrs...))
+}
+
+// requestAttrs returns the attributes attached with AddAttrs.
+func requestAttrs(c *gin.Context) []slog.Attr {
+	v, _ := c.Get(attrsKey)
+	attrs, _ := v.([]slog.Attr)
+	return attrs
+}
+
+// contextKeyAttrs returns the values of the given keys set on c, converted by
+// slog.AnyValue, skipping missing keys.
+func contextKeyAttrs(c *gin.Context, keys []string) []slog.Attr {
+	attrs := make([]slog.Attr, 0, len(keys))
+	for _, k := range keys {
+		if v, ok := c.Get(k); ok {
+			attrs = append(attrs, slog.Any(k, v))
+		}
+	}
+	return attrs
+}
+
+/*
+LazyAttr returns an attribute whose value is computed by fn only when a
+handler resolves it, that is once the record passed the level and sampling
+checks, and at most once however many handlers process the record:
+
+	slog.AddAttrs(c, slog.LazyAttr("ua", func() slog.Value {
+		return slog.GroupValue(parseUserAgent(c.Request.UserAgent())...)
+	}))
+
+The built-in enrichment (WithGeoIP, WithPrincipal, ...) already runs after
+those checks.
+*/
+func LazyAttr(key string, fn func() slog.Value) slog.Attr {
+	return slog.Any(key, lazyValue(sync.OnceValue(fn)))
+}
+
+// lazyValue is a slog.LogValuer computing its value on first use.
+type lazyValue func() slog.Value
+
+// LogValue implements slog.LogValuer.
+func (v lazyValue) LogValue() slog.Value {
+	return v()
+}
@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditLog writes selected requests to a separate audit handler.
+type auditLog struct {
+	handler  slog.Handler
+	selector func(*gin.Context) bool
+}
+
+// log writes the audit record of a handled request if the selector marks it.
+// Audit records are never skipped or sampled.
+func (a *auditLog) log(cfg *config, c *gin.Context, r *request) {
+	if !a.selector(c) {
+		return
+	}
+	end := time.Now()
+	if cfg.utc {
+		end = end.UTC()
+	}
+	status := c.Writer.Status()
+	outcome := "success"
+	if status >= http.StatusBadRequest || r.panic != nil {
+		outcome = "failure"
+	}
+	b := NewRecordBuilder(end, slog.LevelInfo, "Audit")
+	b.names = cfg.fieldNames
+	b.Add(FieldMethod, c.Request.Method).
+		Add(FieldPath, r.path).
+		Add(FieldRoute, r.route).
+		Add(FieldStatus, status).
+		Add(FieldOutcome, outcome).
+		Add(FieldIP, cfg.loggedIP(clientIP(cfg, c))).
+		Add(FieldLatency, end.Sub(r.start))
+	if cfg.principal != nil {
+		subject, extra := cfg.principal(c)
+		b.Add(FieldPrincipal, subject).AddAttrs(extra...)
+	}
+	if id := RequestID(c); id != "" {
+		b.Add(FieldRequestID, id)
+	}
+	if r.traceID != "" {
+		b.Add(FieldTraceID, r.traceID)
+	}
+	if r.spanID != "" {
+		b.Add(FieldSpanID, r.spanID)
+	}
+	_ = a.handler.Handle(context.WithoutCancel(c.Request.Context()), b.Record())
+}
@@ -0,0 +1,43 @@
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/baggage"
+)
+
+// baggageHeader is the W3C baggage header, read when the request context carries no baggage.
+const baggageHeader = "baggage"
+
+// baggageAttr returns the baggage group of the request, with the members named
+// by keys, or every member when keys is empty. The baggage is read from the
+// request context, as set by an OpenTelemetry propagator, or else parsed from
+// the baggage header. It reports false when no member is selected.
+func (cfg *config) baggageAttr(c *gin.Context) (slog.Attr, bool) {
+	bag := baggage.FromContext(c.Request.Context())
+	if bag.Len() == 0 {
+		h := c.GetHeader(baggageHeader)
+		if h == "" {
+			return slog.Attr{}, false
+		}
+		// A malformed member drops the header: Parse returns an empty baggage.
+		bag, _ = baggage.Parse(h)
+	}
+	var attrs []slog.Attr
+	if len(cfg.baggageKeys) == 0 {
+		for _, m := range bag.Members() {
+			attrs = append(attrs, slog.String(m.Key(), m.Value()))
+		}
+	} else {
+		for _, k := range cfg.baggageKeys {
+			if m := bag.Member(k); m.Key() != "" {
+				attrs = append(attrs, slog.String(k, m.Value()))
+			}
+		}
+	}
+	if len(attrs) == 0 {
+		return slog.Attr{}, false
+	}
+	return slog.Attr{Key: cfg.fieldNames.key(FieldBaggage), Value: slog.GroupValue(attrs...)}, true
+}
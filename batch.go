@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+/*
+BatchWriter buffers log output and writes it to the underlying writer in
+batches, once maxRecords writes (one per record for slog handlers) are
+buffered or flushInterval after the first buffered write, whichever comes
+first. Call Flush or Close on shutdown to write what is still buffered.
+*/
+type BatchWriter struct {
+	w          io.Writer
+	maxRecords int
+	interval   time.Duration
+
+	mu     sync.Mutex
+	buf    bytes.Buffer
+	n      int         // buffered writes
+	timer  *time.Timer // pending interval flush
+	closed bool        // writes go straight to w
+}
+
+// NewBatchWriter returns a BatchWriter writing to w.
+func NewBatchWriter(w io.Writer, maxRecords int, flushInterval time.Duration) *BatchWriter {
+	return &BatchWriter{w: w, maxRecords: max(maxRecords, 1), interval: flushInterval}
+}
+
+// Write implements io.Writer. It returns the error of the batch it completes, if any.
+func (b *BatchWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return b.w.Write(p)
+	}
+	b.buf.Write(p)
+	b.n++
+	if b.n >= b.maxRecords {
+		return len(p), b.flush()
+	}
+	if b.timer == nil && b.interval > 0 {
+		b.timer = time.AfterFunc(b.interval, func() { _ = b.Flush() })
+	}
+	return len(p), nil
+}
+
+// Flush writes the buffered output.
+func (b *BatchWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flush()
+}
+
+// Close writes the buffered output and stops batching: later writes go straight
+// to the underlying writer, which is not closed.
+func (b *BatchWriter) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	return b.flush()
+}
+
+func (b *BatchWriter) flush() error {
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	if b.buf.Len() == 0 {
+		return nil
+	}
+	_, err := b.w.Write(b.buf.Bytes())
+	b.buf.Reset()
+	b.n = 0
+	return err
+}
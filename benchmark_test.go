@@ -0,0 +1,86 @@
+package slog
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// discardResponseWriter is a http.ResponseWriter dropping the response, so
+// benchmarks measure the middleware rather than a recorder.
+type discardResponseWriter struct {
+	header http.Header
+}
+
+func (w *discardResponseWriter) Header() http.Header         { return w.header }
+func (w *discardResponseWriter) Write(b []byte) (int, error) { return len(b), nil }
+func (w *discardResponseWriter) WriteHeader(int)             {}
+
+// benchmarkMiddleware serves req through the middleware configured with opts
+// and a no-op handler, with the logs written to io.Discard.
+func benchmarkMiddleware(b *testing.B, req *http.Request, opts ...Option) {
+	b.Helper()
+	gin.SetMode(gin.ReleaseMode)
+	r := gin.New()
+	r.Use(SetLogger(append([]Option{WithWriter(io.Discard)}, opts...)...))
+	r.GET("/a", func(*gin.Context) {})
+	w := &discardResponseWriter{header: http.Header{}}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for b.Loop() {
+		clear(w.header)
+		r.ServeHTTP(w, req)
+	}
+}
+
+func BenchmarkMiddleware(b *testing.B) {
+	b.Run("Default", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil))
+	})
+	b.Run("Query", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a?x=1", nil))
+	})
+	b.Run("JSON", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil),
+			WithHandler(slog.NewJSONHandler(io.Discard, nil)))
+	})
+	// The handler is not enabled for the access log level: no record is built.
+	b.Run("BelowLevel", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil),
+			WithDefaultLevel(slog.LevelDebug),
+			WithHandler(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelInfo})))
+	})
+	b.Run("RequestHeaders", func(b *testing.B) {
+		req := httptest.NewRequest(http.MethodGet, "/a", nil)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", "Bearer token")
+		req.Header.Set("X-Forwarded-Proto", "https")
+		benchmarkMiddleware(b, req, WithRequestHeader(true))
+	})
+	b.Run("Skipped", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil), WithSkipPath([]string{"/a"}))
+	})
+}
+
+// BenchmarkRequestLogger compares deriving the request logger per request
+// with WithLogger to reusing the one derived per key with WithKeyedLogger.
+func BenchmarkRequestLogger(b *testing.B) {
+	tier := func(*gin.Context) string { return "gold" }
+	b.Run("WithLogger", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil),
+			WithLogger(func(c *gin.Context, l *slog.Logger) *slog.Logger {
+				return l.With("tier", tier(c))
+			}))
+	})
+	b.Run("WithKeyedLogger", func(b *testing.B) {
+		benchmarkMiddleware(b, httptest.NewRequest(http.MethodGet, "/a", nil),
+			WithKeyedLogger(tier, func(key string, l *slog.Logger) *slog.Logger {
+				return l.With("tier", key)
+			}))
+	})
+}
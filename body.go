@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"strings"
+)
+
+// bodyCapture tees up to max bytes of a request body as the handlers read it.
+type bodyCapture struct {
+	io.ReadCloser
+	max  int
+	read int64
+	buf  bytes.Buffer
+}
+
+func newBodyCapture(body io.ReadCloser, maxBytes int) *bodyCapture {
+	return &bodyCapture{ReadCloser: body, max: maxBytes}
+}
+
+// Read implements io.Reader.
+func (b *bodyCapture) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.read += int64(n)
+	if rem := b.max - b.buf.Len(); rem > 0 && n > 0 {
+		b.buf.Write(p[:min(n, rem)])
+	}
+	return n, err
+}
+
+// truncated reports whether more bytes were read than captured.
+func (b *bodyCapture) truncated() bool {
+	return b.read > int64(b.buf.Len())
+}
+
+// mediaTypeAllowed reports whether the Content-Type value matches one of the
+// allowed media types. Entries such as "text/*" match a whole type, and a nil
+// list allows any content type.
+func mediaTypeAllowed(contentType string, allowed []string) bool {
+	if allowed == nil {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, a := range allowed {
+		a = strings.ToLower(a)
+		if a == mediaType || (strings.HasSuffix(a, "/*") && strings.HasPrefix(mediaType, a[:len(a)-1])) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package slog
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const handlerTimingKey = "_gin-contrib/slog/handler_timing_"
+
+// handlerTiming is the span of the handlers run after MarkHandlers.
+type handlerTiming struct {
+	start time.Time
+	end   time.Time
+}
+
+/*
+MarkHandlers returns a middleware marking where the route handlers start, for
+WithLatencyBreakdown to split the middleware registered before it from the
+handlers. Register it last:
+
+	r.Use(slog.SetLogger(slog.WithLatencyBreakdown()), auth, slog.MarkHandlers())
+*/
+func MarkHandlers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		t := &handlerTiming{start: time.Now()}
+		c.Set(handlerTimingKey, t)
+		c.Next()
+		t.end = time.Now()
+	}
+}
+
+// writeTimer records the first and last writes of the response body.
+type writeTimer struct {
+	gin.ResponseWriter
+	first time.Time
+	last  time.Time
+}
+
+func (w *writeTimer) mark(start time.Time) {
+	if w.first.IsZero() {
+		w.first = start
+	}
+	w.last = time.Now()
+}
+
+// Write implements io.Writer.
+func (w *writeTimer) Write(b []byte) (int, error) {
+	start := time.Now()
+	n, err := w.ResponseWriter.Write(b)
+	w.mark(start)
+	return n, err
+}
+
+// WriteString implements io.StringWriter.
+func (w *writeTimer) WriteString(s string) (int, error) {
+	start := time.Now()
+	n, err := w.ResponseWriter.WriteString(s)
+	w.mark(start)
+	return n, err
+}
+
+/*
+latencyBreakdownAttr returns the latency_breakdown group of a request: the time
+spent in the middleware before MarkHandlers, when used, in the handlers, and
+writing the response body, from the start of the first write to the end of
+the last one.
+*/
+func (cfg *config) latencyBreakdownAttr(c *gin.Context, r *request) slog.Attr {
+	start, end := r.start, r.end
+	attrs := make([]slog.Attr, 0, 3)
+	if v, ok := c.Get(handlerTimingKey); ok {
+		if t, ok := v.(*handlerTiming); ok && !t.end.IsZero() {
+			attrs = append(attrs, slog.Attr{Key: "middleware", Value: cfg.latencyFormat.value(t.start.Sub(r.start))})
+			start, end = t.start, t.end
+		}
+	}
+	attrs = append(attrs, slog.Attr{Key: "handler", Value: cfg.latencyFormat.value(end.Sub(start))})
+	if !r.timer.first.IsZero() {
+		attrs = append(attrs, slog.Attr{Key: "write", Value: cfg.latencyFormat.value(r.timer.last.Sub(r.timer.first))})
+	}
+	return slog.Attr{Key: cfg.fieldNames.key(FieldLatencyBreakdown), Value: slog.GroupValue(attrs...)}
+}
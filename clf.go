@@ -0,0 +1,72 @@
+package slog
+
+import (
+	"io"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// clfTimeFormat is the NCSA Common Log Format timestamp layout.
+const clfTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// combinedWriter writes NCSA combined log format lines, one at a time.
+type combinedWriter struct {
+	mu       sync.Mutex
+	w        io.Writer
+	redacted map[string]struct{} // query parameters to redact
+}
+
+func (cw *combinedWriter) write(c *gin.Context, rec *AccessRecord, start time.Time) {
+	uri := rec.URI
+	if uri == "" {
+		uri = requestURI(c.Request.URL, cw.redacted)
+	}
+	line := combinedLine(c, rec, uri, start)
+	cw.mu.Lock()
+	defer cw.mu.Unlock()
+	_, _ = cw.w.Write(line)
+}
+
+// combinedLine renders the request as
+//
+//	host - user [time] "method uri proto" status bytes "referer" "user-agent"
+func combinedLine(c *gin.Context, rec *AccessRecord, uri string, start time.Time) []byte {
+	user := "-"
+	if u, _, ok := c.Request.BasicAuth(); ok && u != "" {
+		user = u
+	} else if c.Request.URL.User != nil && c.Request.URL.User.Username() != "" {
+		user = c.Request.URL.User.Username()
+	}
+	size := "-"
+	if rec.BodySize > 0 {
+		size = strconv.Itoa(rec.BodySize)
+	}
+
+	b := make([]byte, 0, 256)
+	b = append(b, rec.IP...)
+	b = append(b, " - "...)
+	b = append(b, user...)
+	b = append(b, " ["...)
+	b = start.AppendFormat(b, clfTimeFormat)
+	b = append(b, "] "...)
+	b = strconv.AppendQuote(b, rec.Method+" "+uri+" "+c.Request.Proto)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, int64(rec.Status), 10)
+	b = append(b, ' ')
+	b = append(b, size...)
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, orDash(rec.Referer))
+	b = append(b, ' ')
+	b = strconv.AppendQuote(b, orDash(rec.UserAgent))
+	return append(b, '\n')
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
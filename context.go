@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// loggerContextKey is the context.Context key of the request-scoped logger.
+type loggerContextKey struct{}
+
+// NewContext returns a copy of ctx carrying the logger.
+func NewContext(ctx context.Context, l *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, l)
+}
+
+/*
+FromContext returns the request-scoped logger stored in ctx by the middleware,
+which also stores it in c.Request.Context(), so code that only receives a
+context can log with the request's attributes. It returns slog.Default() when
+ctx carries no logger.
+*/
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx == nil {
+		return slog.Default()
+	}
+	if l, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return l
+	}
+	return slog.Default()
+}
+
+/*
+Go runs fn in a new goroutine with the request-scoped logger and a context
+detached from the request's cancellation, so work continued after the response
+is written still logs with the originating request's attributes (request id,
+trace ids, ...). The context keeps the request context's values.
+
+Parameters:
+
+	c - the gin.Context of the originating request.
+	fn - the background work.
+*/
+func Go(c *gin.Context, fn func(ctx context.Context, l *slog.Logger)) {
+	l := GetOrDefault(c)
+	ctx := context.Background()
+	if c.Request != nil {
+		ctx = context.WithoutCancel(c.Request.Context())
+	}
+	ctx = NewContext(ctx, l)
+	go fn(ctx, l)
+}
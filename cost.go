@@ -0,0 +1,56 @@
+package slog
+
+import (
+	"io"
+	"log/slog"
+	"runtime/metrics"
+	"time"
+)
+
+// cpuMetric is the runtime's estimate of the CPU time spent by the process.
+const cpuMetric = "/cpu/classes/total:cpu-seconds"
+
+// bodyCounter counts the request body bytes read by the handlers.
+type bodyCounter struct {
+	io.ReadCloser
+	n int64
+}
+
+// Read implements io.Reader.
+func (b *bodyCounter) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+// cpuSeconds returns the process CPU time estimated by the runtime.
+func cpuSeconds() float64 {
+	s := []metrics.Sample{{Name: cpuMetric}}
+	metrics.Read(s)
+	if s[0].Value.Kind() != metrics.KindFloat64 {
+		return 0
+	}
+	return s[0].Value.Float64()
+}
+
+// cost tracks the resources used while serving a request.
+type cost struct {
+	body *bodyCounter
+	cpu  float64
+}
+
+// attr returns the cost summary group. cpu_hint is the process-wide CPU time
+// delta over the request, which the runtime refreshes coarsely, so it is only
+// meaningful aggregated over many requests.
+func (c *cost) attr(key string, bytesOut int, duration time.Duration) slog.Attr {
+	var in int64
+	if c.body != nil {
+		in = c.body.n
+	}
+	return slog.Group(key,
+		slog.Int64("bytes_in", in),
+		slog.Int("bytes_out", max(bytesOut, 0)),
+		slog.Duration("duration", duration),
+		slog.Float64("cpu_hint", cpuSeconds()-c.cpu),
+	)
+}
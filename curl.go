@@ -0,0 +1,42 @@
+package slog
+
+import (
+	"net/http"
+	"strings"
+)
+
+// curlRepro configures the reproduction curl command of failed requests.
+type curlRepro struct {
+	minStatus int
+	headers   []string
+}
+
+// command returns a curl command reproducing r: its quoted method, URL with redacted
+// query values, the selected headers with hidden headers redacted, and the
+// captured body, if any.
+func (p *curlRepro) command(cfg *config, r *http.Request, body *bodyCapture) string {
+	var b strings.Builder
+	b.WriteString("curl -X ")
+	b.WriteString(shellQuote(r.Method))
+	b.WriteByte(' ')
+	b.WriteString(shellQuote(requestScheme(r) + "://" + r.Host + requestURI(r.URL, cfg.redactedQueryParams)))
+	for _, name := range p.headers {
+		for _, v := range r.Header.Values(name) {
+			if cfg.hiddenHeaders.contains(name) {
+				v = redactedValue
+			}
+			b.WriteString(" -H ")
+			b.WriteString(shellQuote(http.CanonicalHeaderKey(name) + ": " + v))
+		}
+	}
+	if body != nil && body.buf.Len() > 0 {
+		b.WriteString(" --data-raw ")
+		b.WriteString(shellQuote(body.buf.String()))
+	}
+	return b.String()
+}
+
+// shellQuote quotes s for POSIX shells.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,187 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// debugCaptureBytes bounds the bodies captured for debug requests.
+const debugCaptureBytes = 64 << 10
+
+// maxDebugRequests bounds the number of pending debug request ids.
+const maxDebugRequests = 1000
+
+/*
+DebugRequests is a registry of request ids that get a full capture (visible
+headers, request and response bodies, debug level, no skipping or sampling)
+on their next occurrence. The records of such a request, including those
+logged with Get(c), are written whatever the level of the handler and sinks.
+Register it with WithDebugRequests and expose Handler on an internal admin route:
+
+	debug := slog.NewDebugRequests()
+	r.Use(slog.SetLogger(slog.WithRequestID(""), slog.WithDebugRequests(debug)))
+	admin.Any("/debug-requests", debug.Handler())
+*/
+type DebugRequests struct {
+	mu  sync.Mutex
+	ids map[string]struct{}
+}
+
+// NewDebugRequests returns an empty registry.
+func NewDebugRequests() *DebugRequests {
+	return &DebugRequests{ids: map[string]struct{}{}}
+}
+
+// Add registers request ids for a full capture on their next occurrence.
+func (d *DebugRequests) Add(ids ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, id := range ids {
+		if id != "" && len(d.ids) < maxDebugRequests {
+			d.ids[id] = struct{}{}
+		}
+	}
+}
+
+// Remove unregisters a request id.
+func (d *DebugRequests) Remove(id string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.ids, id)
+}
+
+// IDs returns the pending request ids, sorted.
+func (d *DebugRequests) IDs() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	ids := make([]string, 0, len(d.ids))
+	for id := range d.ids {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// take reports whether id is registered, unregistering it.
+func (d *DebugRequests) take(id string) bool {
+	if id == "" {
+		return false
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if _, ok := d.ids[id]; !ok {
+		return false
+	}
+	delete(d.ids, id)
+	return true
+}
+
+/*
+Handler returns an admin endpoint for the registry:
+  - GET lists the pending request ids.
+  - POST or PUT with a {"request_id": "..."} JSON body registers an id.
+  - DELETE with a request_id query parameter unregisters an id.
+
+The endpoint should only be reachable by operators.
+*/
+func (d *DebugRequests) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet:
+		case http.MethodPost, http.MethodPut:
+			var body struct {
+				RequestID string `json:"request_id" binding:"required"`
+			}
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			d.Add(body.RequestID)
+		case http.MethodDelete:
+			d.Remove(c.Query("request_id"))
+		default:
+			c.AbortWithStatus(http.StatusMethodNotAllowed)
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"request_ids": d.IDs()})
+	}
+}
+
+// debugRequestID returns the id used to look up debug requests.
+func debugRequestID(c *gin.Context) string {
+	if id := RequestID(c); id != "" {
+		return id
+	}
+	return c.GetHeader(requestIDHeader)
+}
+
+// levelHeader is a request header asking for a full capture of the request.
+type levelHeader struct {
+	name    string
+	allowed func(*gin.Context) bool
+}
+
+// requested reports whether the request carries the header and is allowed to use it.
+func (h *levelHeader) requested(c *gin.Context) bool {
+	return c.GetHeader(h.name) != "" && h.allowed(c)
+}
+
+// debugConfig returns a copy of the config performing a full capture.
+func (cfg *config) debugConfig() *config {
+	dc := *cfg
+	dc.defaultLeveler = slog.LevelDebug
+	dc.pathLevels = nil
+	dc.routeLevels = nil
+	dc.methodLevels = nil
+	dc.redirectLevel = nil
+	dc.levelFile = nil
+	dc.cidrLevels = nil
+	dc.withRequestHeader = true
+	dc.requestBodyMax = max(cfg.requestBodyMax, debugCaptureBytes)
+	dc.requestBodyTypes = nil
+	dc.responseBodyMax = max(cfg.responseBodyMax, debugCaptureBytes)
+	return &dc
+}
+
+// debugContextKey marks the context of the records of a debug request.
+type debugContextKey struct{}
+
+// isDebugContext reports whether ctx is the context of a debug request record.
+func isDebugContext(ctx context.Context) bool {
+	return ctx != nil && ctx.Value(debugContextKey{}) != nil
+}
+
+// debugHandler is the handler of the logger of a debug request: it enables
+// every level, and marks the context of the records it handles so sinks with
+// their own level, such as those of WithTee, write them too.
+type debugHandler struct {
+	next slog.Handler
+}
+
+// Enabled implements slog.Handler.
+func (debugHandler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler.
+func (h debugHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return h.next.Handle(context.WithValue(ctx, debugContextKey{}, true), r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h debugHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return debugHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h debugHandler) WithGroup(name string) slog.Handler {
+	return debugHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,70 @@
+package slog
+
+import (
+	"log/slog"
+	"strconv"
+)
+
+// ConflictPolicy decides how record attributes sharing a key are resolved.
+type ConflictPolicy int
+
+const (
+	// ConflictAllow leaves duplicate keys untouched (default).
+	ConflictAllow ConflictPolicy = iota
+	// ConflictOverride keeps the last value for a key, so custom fields replace built-ins.
+	ConflictOverride
+	// ConflictKeepFirst keeps the first value for a key, so built-ins win.
+	ConflictKeepFirst
+	// ConflictSuffix keeps every value, renaming later duplicates to key_2, key_3, ...
+	ConflictSuffix
+)
+
+// dedupeRecord returns a copy of r whose top-level attribute keys are unique
+// according to the policy. Records without duplicates are returned as is.
+func dedupeRecord(r slog.Record, policy ConflictPolicy) slog.Record {
+	if policy == ConflictAllow {
+		return r
+	}
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	index := make(map[string]int, r.NumAttrs())
+	dup := false
+	r.Attrs(func(a slog.Attr) bool {
+		if _, ok := index[a.Key]; ok {
+			dup = true
+		} else {
+			index[a.Key] = len(attrs)
+		}
+		attrs = append(attrs, a)
+		return true
+	})
+	if !dup {
+		return r
+	}
+
+	out := make([]slog.Attr, 0, len(attrs))
+	seen := make(map[string]int, len(attrs))
+	for _, a := range attrs {
+		i, ok := seen[a.Key]
+		switch {
+		case !ok:
+			seen[a.Key] = len(out)
+			out = append(out, a)
+		case policy == ConflictOverride:
+			out[i].Value = a.Value
+		case policy == ConflictSuffix:
+			for n := 2; ; n++ {
+				key := a.Key + "_" + strconv.Itoa(n)
+				if _, taken := index[key]; !taken {
+					index[key] = len(out)
+					seen[key] = len(out)
+					out = append(out, slog.Attr{Key: key, Value: a.Value})
+					break
+				}
+			}
+		}
+	}
+
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	nr.AddAttrs(out...)
+	return nr
+}
@@ -0,0 +1,86 @@
+package slog
+
+import (
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"hash"
+	"io"
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DigestAlgorithm is a hash algorithm for WithBodyDigest.
+type DigestAlgorithm string
+
+// Digest algorithms supported by WithBodyDigest.
+const (
+	DigestSHA256 DigestAlgorithm = "sha256"
+	DigestSHA512 DigestAlgorithm = "sha512"
+)
+
+// new returns a hash computing a, or nil if a is not supported.
+func (a DigestAlgorithm) new() hash.Hash {
+	switch a {
+	case DigestSHA256:
+		return sha256.New()
+	case DigestSHA512:
+		return sha512.New()
+	default:
+		return nil
+	}
+}
+
+// bodyDigest hashes and counts the bytes of a body.
+type bodyDigest struct {
+	alg DigestAlgorithm
+	h   hash.Hash
+	n   int64
+}
+
+func (d *bodyDigest) write(p []byte) {
+	d.h.Write(p)
+	d.n += int64(len(p))
+}
+
+// attrs returns the hex digest as key_<algorithm> and the byte count as bytesKey.
+func (d *bodyDigest) attrs(key, bytesKey string) []slog.Attr {
+	return []slog.Attr{
+		slog.String(key+"_"+string(d.alg), hex.EncodeToString(d.h.Sum(nil))),
+		slog.Int64(bytesKey, d.n),
+	}
+}
+
+// digestReader hashes a request body as the handlers read it.
+type digestReader struct {
+	io.ReadCloser
+	bodyDigest
+}
+
+// Read implements io.Reader.
+func (r *digestReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.write(p[:n])
+	return n, err
+}
+
+// digestWriter hashes the response body as it is written.
+type digestWriter struct {
+	gin.ResponseWriter
+	bodyDigest
+}
+
+// Write implements io.Writer.
+func (w *digestWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.write(b[:n])
+	return n, err
+}
+
+// WriteString implements io.StringWriter.
+func (w *digestWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.write([]byte(s[:n]))
+	return n, err
+}
@@ -0,0 +1,114 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+)
+
+/*
+Stats counts the decisions of a middleware configured with WithDryRun: the
+requests it saw, why those it would not log were dropped, and the levels of
+those it would log. It is safe for concurrent use.
+
+	stats := &slog.Stats{}
+	r.Use(
+		slog.SetLogger(current...),
+		slog.SetLogger(append(candidate, slog.WithDryRun(stats))...),
+	)
+	...
+	fmt.Printf("%+v\n", stats.Snapshot())
+*/
+type Stats struct {
+	counts [numStats]atomic.Int64
+
+	mu     sync.Mutex
+	levels map[slog.Level]int64
+}
+
+// stat is a counter of Stats.
+type stat int
+
+const (
+	statRequests stat = iota
+	statSkipped
+	statSampledOut
+	statFiltered
+	statBelowLevel
+	statRateLimited
+	numStats
+)
+
+// StatsSnapshot is a copy of the counters of Stats.
+type StatsSnapshot struct {
+	Requests    int64            `json:"requests"`     // requests handled
+	Skipped     int64            `json:"skipped"`      // by skip paths, skippers, or the not-found summary
+	SampledOut  int64            `json:"sampled_out"`  // by the sample rates or sampler
+	Filtered    int64            `json:"filtered"`     // by WithErrorsOnly, WithMinLatency or WithFilters
+	BelowLevel  int64            `json:"below_level"`  // below the level of the handler
+	RateLimited int64            `json:"rate_limited"` // by WithMaxLogsPerSecond
+	Logged      map[string]int64 `json:"logged"`       // records that would be written, by level
+}
+
+// Snapshot returns the current counters.
+func (s *Stats) Snapshot() StatsSnapshot {
+	snap := StatsSnapshot{
+		Requests:    s.counts[statRequests].Load(),
+		Skipped:     s.counts[statSkipped].Load(),
+		SampledOut:  s.counts[statSampledOut].Load(),
+		Filtered:    s.counts[statFiltered].Load(),
+		BelowLevel:  s.counts[statBelowLevel].Load(),
+		RateLimited: s.counts[statRateLimited].Load(),
+		Logged:      map[string]int64{},
+	}
+	s.mu.Lock()
+	for l, n := range s.levels {
+		snap.Logged[l.String()] = n
+	}
+	s.mu.Unlock()
+	return snap
+}
+
+// add increments a counter. It is a no-op on a nil Stats, outside dry runs.
+func (s *Stats) add(st stat) {
+	if s != nil {
+		s.counts[st].Add(1)
+	}
+}
+
+// logged counts a record that would be written at level.
+func (s *Stats) logged(level slog.Level) {
+	s.mu.Lock()
+	if s.levels == nil {
+		s.levels = map[slog.Level]int64{}
+	}
+	s.levels[level]++
+	s.mu.Unlock()
+}
+
+// dropHandler discards the records of a dry run after the handlers wrapping
+// it, such as redaction and encryption, have processed them.
+type dropHandler struct {
+	next slog.Handler
+}
+
+// Enabled implements slog.Handler.
+func (h dropHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (dropHandler) Handle(context.Context, slog.Record) error {
+	return nil
+}
+
+// WithAttrs implements slog.Handler.
+func (h dropHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return dropHandler{next: h.next.WithAttrs(attrs)}
+}
+
+// WithGroup implements slog.Handler.
+func (h dropHandler) WithGroup(name string) slog.Handler {
+	return dropHandler{next: h.next.WithGroup(name)}
+}
@@ -0,0 +1,177 @@
+package slog
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// encryptedPrefix marks encrypted attribute values: "enc:<key id>:<base64 nonce+ciphertext>".
+const encryptedPrefix = "enc:"
+
+/*
+KeyProvider supplies the AES key used to encrypt the attributes selected with
+WithEncryptedFields. Key returns the id of the key, which is stored alongside
+the ciphertext so rotated keys can still be looked up to decrypt, and the 16,
+24 or 32 byte key itself.
+*/
+type KeyProvider interface {
+	Key() (id string, key []byte, err error)
+}
+
+type staticKey struct {
+	id  string
+	key []byte
+}
+
+func (k staticKey) Key() (string, []byte, error) {
+	return k.id, k.key, nil
+}
+
+// StaticKey returns a KeyProvider that always supplies key under the given id.
+func StaticKey(id string, key []byte) KeyProvider {
+	return staticKey{id: id, key: key}
+}
+
+/*
+DecryptValue decrypts an attribute value encrypted by the middleware. The key
+function returns the key for the id stored with the value.
+
+Parameters:
+
+	value - the logged value, as written by the handler.
+	key - looks up a key by id.
+
+Returns:
+
+	string - the plain text value.
+	error - if the value is not encrypted or cannot be decrypted.
+*/
+func DecryptValue(value string, key func(id string) ([]byte, error)) (string, error) {
+	rest, ok := strings.CutPrefix(value, encryptedPrefix)
+	if !ok {
+		return "", errors.New("slog: value is not encrypted")
+	}
+	id, data, ok := strings.Cut(rest, ":")
+	if !ok {
+		return "", errors.New("slog: malformed encrypted value")
+	}
+	raw, err := base64.RawStdEncoding.DecodeString(data)
+	if err != nil {
+		return "", err
+	}
+	k, err := key(id)
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(k)
+	if err != nil {
+		return "", err
+	}
+	if len(raw) < aead.NonceSize() {
+		return "", errors.New("slog: malformed encrypted value")
+	}
+	nonce, ciphertext := raw[:aead.NonceSize()], raw[aead.NonceSize():]
+	plain, err := aead.Open(nil, nonce, ciphertext, []byte(id))
+	if err != nil {
+		return "", err
+	}
+	return string(plain), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encrypt returns the encrypted form of the value's string representation.
+func encrypt(keys KeyProvider, v slog.Value) (string, error) {
+	id, key, err := keys.Key()
+	if err != nil {
+		return "", err
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(v.Resolve().String()), []byte(id))
+	return encryptedPrefix + id + ":" + base64.RawStdEncoding.EncodeToString(sealed), nil
+}
+
+// encryptHandler encrypts the values of selected top-level attributes, both
+// in records and in attributes added with Logger.With, before passing them on.
+// Values that fail to encrypt are redacted.
+type encryptHandler struct {
+	next   slog.Handler
+	fields map[string]struct{}
+	keys   KeyProvider
+}
+
+func (h *encryptHandler) encryptAttrs(attrs []slog.Attr) []slog.Attr {
+	out := attrs
+	copied := false
+	for i, a := range attrs {
+		if _, ok := h.fields[a.Key]; !ok {
+			continue
+		}
+		if !copied {
+			out = slices.Clone(attrs)
+			copied = true
+		}
+		s, err := encrypt(h.keys, a.Value)
+		if err != nil {
+			s = redactedValue
+		}
+		out[i] = slog.String(a.Key, s)
+	}
+	return out
+}
+
+// Enabled implements slog.Handler.
+func (h *encryptHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *encryptHandler) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	nr := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	if len(attrs) > 0 {
+		nr.AddAttrs(h.encryptAttrs(attrs)...)
+	}
+	return h.next.Handle(ctx, nr)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *encryptHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &encryptHandler{next: h.next.WithAttrs(h.encryptAttrs(attrs)), fields: h.fields, keys: h.keys}
+}
+
+// WithGroup implements slog.Handler. Attributes inside the group are not
+// top-level, so they are passed on unencrypted.
+func (h *encryptHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.next.WithGroup(name)
+}
@@ -0,0 +1,79 @@
+package slog
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const errorIDKey = "_gin-contrib/slog/error_id_"
+
+// newErrorID returns a short random id, easy to read out from a screenshot.
+func newErrorID() string {
+	var b [5]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// errorIDWriter assigns the error id of a request, and sets its response
+// header, once a 5xx status is about to be written.
+type errorIDWriter struct {
+	gin.ResponseWriter
+	header string
+	id     string
+}
+
+// ensure returns the error id, generating it and setting the header on first use.
+func (w *errorIDWriter) ensure() string {
+	if w.id == "" {
+		w.id = newErrorID()
+		w.Header().Set(w.header, w.id)
+	}
+	return w.id
+}
+
+// check assigns the error id if the pending status is a 5xx and the header
+// has not been written yet.
+func (w *errorIDWriter) check() {
+	if w.Status() >= http.StatusInternalServerError && !w.Written() {
+		w.ensure()
+	}
+}
+
+// WriteHeader implements http.ResponseWriter.
+func (w *errorIDWriter) WriteHeader(code int) {
+	w.ResponseWriter.WriteHeader(code)
+	w.check()
+}
+
+// Write implements io.Writer.
+func (w *errorIDWriter) Write(b []byte) (int, error) {
+	w.check()
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString implements io.StringWriter.
+func (w *errorIDWriter) WriteString(s string) (int, error) {
+	w.check()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// WriteHeaderNow implements gin.ResponseWriter.
+func (w *errorIDWriter) WriteHeaderNow() {
+	w.check()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// ErrorID returns the error id of the request, for including in an error
+// response body, when the middleware is configured with WithErrorID. It
+// generates the id, and sets its response header, if the response has none
+// yet. It returns an empty string without WithErrorID.
+func ErrorID(c *gin.Context) string {
+	w, ok := c.Value(errorIDKey).(*errorIDWriter)
+	if !ok {
+		return ""
+	}
+	return w.ensure()
+}
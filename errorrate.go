@@ -0,0 +1,80 @@
+package slog
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// errorRateBuckets is the number of slots a window is divided into.
+	errorRateBuckets = 10
+	// errorRateMinRequests is the number of requests in the window below which
+	// the alert does not fire, so a single early failure is not a 100% rate.
+	errorRateMinRequests = 10
+)
+
+// errorRateBucket counts the requests of one slot of the window.
+type errorRateBucket struct {
+	start  time.Time
+	total  int
+	errors int
+}
+
+/*
+errorRate tracks the ratio of 5xx responses over a sliding window and calls
+alert when it rises above threshold. It fires once per crossing and rearms
+when the ratio drops back to the threshold or below.
+*/
+type errorRate struct {
+	mu        sync.Mutex
+	slot      time.Duration
+	threshold float64
+	alert     func(rate float64, requests int)
+	buckets   [errorRateBuckets]errorRateBucket
+	firing    bool
+}
+
+func newErrorRate(window time.Duration, threshold float64, alert func(rate float64, requests int)) *errorRate {
+	return &errorRate{slot: max(window/errorRateBuckets, time.Millisecond), threshold: threshold, alert: alert}
+}
+
+// observe records a response status and fires the alert on a crossing.
+func (e *errorRate) observe(status int, now time.Time) {
+	e.mu.Lock()
+	start := now.Truncate(e.slot)
+	b := &e.buckets[(start.UnixNano()/int64(e.slot))%errorRateBuckets]
+	switch {
+	case b.start.After(start):
+		// A late observation of a window the bucket has since been reused for.
+		e.mu.Unlock()
+		return
+	case b.start.Before(start):
+		*b = errorRateBucket{start: start}
+	}
+	b.total++
+	if status >= http.StatusInternalServerError {
+		b.errors++
+	}
+	total, errs := 0, 0
+	oldest := start.Add(-e.slot * (errorRateBuckets - 1))
+	for i := range e.buckets {
+		if !e.buckets[i].start.Before(oldest) {
+			total += e.buckets[i].total
+			errs += e.buckets[i].errors
+		}
+	}
+	rate := float64(errs) / float64(total)
+	fire := false
+	switch {
+	case rate > e.threshold && total >= errorRateMinRequests:
+		fire = !e.firing
+		e.firing = true
+	case rate <= e.threshold:
+		e.firing = false
+	}
+	e.mu.Unlock()
+	if fire {
+		e.alert(rate, total)
+	}
+}
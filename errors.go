@@ -0,0 +1,108 @@
+package slog
+
+import (
+	"log/slog"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errorTypeNames names the gin.ErrorType bits, checked in order so errors
+// carrying several type bits are named once.
+var errorTypeNames = []struct {
+	typ  gin.ErrorType
+	name string
+}{
+	{gin.ErrorTypeBind, "bind"},
+	{gin.ErrorTypeRender, "render"},
+	{gin.ErrorTypePrivate, "private"},
+	{gin.ErrorTypePublic, "public"},
+}
+
+// errorTypeName returns the name of the error's type.
+func errorTypeName(err *gin.Error) string {
+	for _, t := range errorTypeNames {
+		if err.IsType(t.typ) {
+			return t.name
+		}
+	}
+	return "other"
+}
+
+// errorsAttr returns the errors group: one group per error, keyed by its
+// index, holding its message, type and metadata, and its stack frames when
+// withStack is set and the error records a stack.
+func errorsAttr(key string, errs []*gin.Error, withStack bool) slog.Attr {
+	attrs := make([]slog.Attr, len(errs))
+	for i, err := range errs {
+		fields := []slog.Attr{
+			slog.String("message", err.Error()),
+			slog.String("type", errorTypeName(err)),
+		}
+		if err.Meta != nil {
+			fields = append(fields, slog.Any("meta", err.Meta))
+		}
+		if withStack {
+			if frames := errorStack(err.Err); frames != nil {
+				fields = append(fields, slog.Any("stack", frames))
+			}
+		}
+		attrs[i] = slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(fields...)}
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+// errorTypeKey returns the attribute key for the error's type.
+func errorTypeKey(err *gin.Error) string {
+	return errorTypeName(err) + "_errors"
+}
+
+// errorTypeAttrs groups errors by type into one attribute per type, each
+// holding the errors' JSON representation including their metadata, followed
+// by the number of errors of each type as the countsKey group. Metadata
+// implementing slog.LogValuer is logged as its LogValue, next to the error
+// message, rather than through its JSON encoding.
+func errorTypeAttrs(errs []*gin.Error, countsKey string) []slog.Attr {
+	if len(errs) == 0 {
+		return nil
+	}
+	byKey := map[string][]any{}
+	var keys []string
+	for _, err := range errs {
+		key := errorTypeKey(err)
+		if _, ok := byKey[key]; !ok {
+			keys = append(keys, key)
+		}
+		byKey[key] = append(byKey[key], errorValue(err))
+	}
+	attrs := make([]slog.Attr, 0, len(keys)+1)
+	counts := make([]slog.Attr, 0, len(keys))
+	for _, key := range keys {
+		attrs = append(attrs, slog.Any(key, byKey[key]))
+		counts = append(counts, slog.Int(strings.TrimSuffix(key, "_errors"), len(byKey[key])))
+	}
+	return append(attrs, slog.Attr{Key: countsKey, Value: slog.GroupValue(counts...)})
+}
+
+// errorValue returns the logged form of an error: gin's JSON representation,
+// or its message and the LogValue of its metadata when that is a slog.LogValuer.
+func errorValue(err *gin.Error) any {
+	if lv, ok := err.Meta.(slog.LogValuer); ok {
+		return gin.H{"error": err.Error(), "meta": plainValue(lv.LogValue())}
+	}
+	return err.JSON()
+}
+
+// plainValue converts v to Go values that encode like v: groups become maps.
+func plainValue(v slog.Value) any {
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return v.Any()
+	}
+	m := make(map[string]any, len(v.Group()))
+	for _, a := range v.Group() {
+		m[a.Key] = plainValue(a.Value)
+	}
+	return m
+}
@@ -0,0 +1,91 @@
+package slog
+
+import (
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+const errorOutputKey = "_gin-contrib/slog/error_output_"
+
+// ansiEscape matches the color sequences gin adds to its console output.
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+/*
+ErrorWriter attributes gin's error output to requests. While a request is
+served by a middleware configured with WithErrorWriter, the writer returned by
+For(c) collects the output into that request's access record as the
+error_output attribute, instead of it being interleaved in stderr. Output
+written outside such requests, or to the ErrorWriter itself, is forwarded to
+the fallback writer.
+
+Recovery replaces gin.Recovery, writing the panic and its stack to For(c):
+
+	ew := slog.NewErrorWriter(os.Stderr)
+	r.Use(slog.SetLogger(slog.WithErrorWriter(ew)), ew.Recovery())
+*/
+type ErrorWriter struct {
+	fallback io.Writer
+}
+
+// NewErrorWriter returns an ErrorWriter that forwards uncaptured output to fallback.
+func NewErrorWriter(fallback io.Writer) *ErrorWriter {
+	if fallback == nil {
+		fallback = io.Discard
+	}
+	return &ErrorWriter{fallback: fallback}
+}
+
+// Write implements io.Writer, forwarding p to the fallback writer.
+func (w *ErrorWriter) Write(p []byte) (int, error) {
+	return w.fallback.Write(p)
+}
+
+// For returns the writer capturing the error output of the request served by
+// c, or the fallback writer outside a middleware configured with WithErrorWriter.
+func (w *ErrorWriter) For(c *gin.Context) io.Writer {
+	if ec, ok := c.Value(errorOutputKey).(*errorCapture); ok && ec != nil {
+		return ec
+	}
+	return w.fallback
+}
+
+// Recovery returns gin's Recovery middleware writing to For(c).
+func (w *ErrorWriter) Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		gin.RecoveryWithWriter(w.For(c))(c)
+	}
+}
+
+// errorCapture collects the error output of a request, one line per write,
+// without the color sequences.
+type errorCapture struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+// Write implements io.Writer.
+func (ec *errorCapture) Write(p []byte) (int, error) {
+	if text := strings.TrimSpace(ansiEscape.ReplaceAllString(string(p), "")); text != "" {
+		ec.mu.Lock()
+		ec.lines = append(ec.lines, text)
+		ec.mu.Unlock()
+	}
+	return len(p), nil
+}
+
+// captureErrors runs fn with an error output capture stored in c, restoring
+// the capture of any outer middleware afterwards, and returns the lines written.
+func captureErrors(c *gin.Context, fn func()) []string {
+	ec := &errorCapture{}
+	outer, _ := c.Get(errorOutputKey)
+	c.Set(errorOutputKey, ec)
+	defer c.Set(errorOutputKey, outer)
+	fn()
+	ec.mu.Lock()
+	defer ec.mu.Unlock()
+	return ec.lines
+}
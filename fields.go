@@ -0,0 +1,187 @@
+package slog
+
+import (
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// Field is the key of a built-in access log attribute.
+type Field string
+
+// Built-in access log attribute keys.
+const (
+	FieldStatus               Field = "status"
+	FieldOutcome              Field = "outcome"
+	FieldMethod               Field = "method"
+	FieldPath                 Field = "path"
+	FieldQuery                Field = "query"
+	FieldURI                  Field = "uri"
+	FieldProto                Field = "proto"
+	FieldHost                 Field = "host"
+	FieldScheme               Field = "scheme"
+	FieldRoute                Field = "route"
+	FieldGraphQLOperation     Field = "graphql_operation"
+	FieldGraphQLType          Field = "graphql_type"
+	FieldIP                   Field = "ip"
+	FieldForwardedFor         Field = "forwarded_for"
+	FieldForwarded            Field = "forwarded"
+	FieldProxyChain           Field = "proxy_chain"
+	FieldGeo                  Field = "geo"
+	FieldLatency              Field = "latency"
+	FieldLatencyBreakdown     Field = "latency_breakdown"
+	FieldLatencyBucket        Field = "latency_bucket"
+	FieldSlow                 Field = "slow"
+	FieldLargeResponse        Field = "large_response"
+	FieldTTFB                 Field = "ttfb"
+	FieldStreamDuration       Field = "stream_duration"
+	FieldReferer              Field = "referer"
+	FieldUserAgent            Field = "user_agent"
+	FieldPrincipal            Field = "principal"
+	FieldBodySize             Field = "body_size"
+	FieldRequestSize          Field = "request_size"
+	FieldRequestBodyRead      Field = "request_body_read"
+	FieldRequestContentType   Field = "request_content_type"
+	FieldResponseContentType  Field = "response_content_type"
+	FieldHijacked             Field = "hijacked"
+	FieldClientAborted        Field = "client_aborted"
+	FieldContextError         Field = "context_error"
+	FieldDeadlineBudget       Field = "deadline_budget"
+	FieldDeadlineRemaining    Field = "deadline_remaining"
+	FieldTimedOut             Field = "timed_out"
+	FieldRequestID            Field = "request_id"
+	FieldErrorID              Field = "error_id"
+	FieldTenant               Field = "tenant"
+	FieldBaggage              Field = "baggage"
+	FieldTraceID              Field = "trace_id"
+	FieldSpanID               Field = "span_id"
+	FieldHeaders              Field = "headers"
+	FieldRequestBody          Field = "request_body"
+	FieldRequestBodyTruncated Field = "request_body_truncated"
+	FieldRequestBodyBytes     Field = "request_body_bytes"
+	FieldMultipart            Field = "multipart"
+	FieldResponseBody         Field = "response_body"
+	FieldResponseBodyBytes    Field = "response_body_bytes"
+	FieldCost                 Field = "cost"
+	FieldPanic                Field = "panic"
+	FieldStack                Field = "stack"
+	FieldErrorOutput          Field = "error_output"
+	FieldErrors               Field = "errors"
+	FieldErrorCounts          Field = "error_counts"
+	FieldDebugCapture         Field = "debug_capture"
+	FieldSampledOut           Field = "sampled_out"
+	FieldCurl                 Field = "curl"
+)
+
+// selectableFields are the built-in fields WithFields chooses from.
+var selectableFields = map[Field]struct{}{
+	FieldStatus:    {},
+	FieldMethod:    {},
+	FieldPath:      {},
+	FieldQuery:     {},
+	FieldRoute:     {},
+	FieldIP:        {},
+	FieldLatency:   {},
+	FieldReferer:   {},
+	FieldUserAgent: {},
+	FieldBodySize:  {},
+}
+
+// FieldNames maps built-in fields to the attribute keys they are logged under.
+type FieldNames map[Field]string
+
+// key returns the attribute key of f.
+func (names FieldNames) key(f Field) string {
+	if k, ok := names[f]; ok {
+		return k
+	}
+	return string(f)
+}
+
+// layoutFields selects no request fields, for layouts that render them.
+var layoutFields = map[Field]struct{}{}
+
+// attrPool recycles the attribute buffers of record builders.
+var attrPool = sync.Pool{
+	New: func() any {
+		attrs := make([]slog.Attr, 0, 24)
+		return &attrs
+	},
+}
+
+// RecordBuilder builds a slog.Record from Field keyed attributes. Attributes
+// are collected in a pooled buffer and copied into the record at once.
+type RecordBuilder struct {
+	time   time.Time
+	level  slog.Level
+	msg    string
+	attrs  *[]slog.Attr       // pooled buffer, nil once released
+	fields map[Field]struct{} // selected fields, nil keeps all
+	names  FieldNames         // renamed keys
+	group  string             // group holding the attributes, if any
+}
+
+// NewRecordBuilder returns a builder for a record with the given time, level and message.
+func NewRecordBuilder(t time.Time, level slog.Level, msg string) *RecordBuilder {
+	return &RecordBuilder{time: t, level: level, msg: msg, attrs: attrPool.Get().(*[]slog.Attr)}
+}
+
+// Add adds the attribute for field f.
+func (b *RecordBuilder) Add(f Field, value any) *RecordBuilder {
+	return b.add(f, slog.AnyValue(value))
+}
+
+// add adds the attribute for field f unless WithFields deselected it.
+func (b *RecordBuilder) add(f Field, v slog.Value) *RecordBuilder {
+	if b.fields != nil {
+		if _, selectable := selectableFields[f]; selectable {
+			if _, ok := b.fields[f]; !ok {
+				return b
+			}
+		}
+	}
+	return b.AddAttrs(slog.Attr{Key: b.names.key(f), Value: v})
+}
+
+// AddAttrs adds arbitrary attributes.
+func (b *RecordBuilder) AddAttrs(attrs ...slog.Attr) *RecordBuilder {
+	if b.attrs == nil {
+		b.attrs = attrPool.Get().(*[]slog.Attr)
+	}
+	*b.attrs = append(*b.attrs, attrs...)
+	return b
+}
+
+// Record returns the built record and releases the builder's buffer, so
+// attributes added afterwards start a new record.
+func (b *RecordBuilder) Record() slog.Record {
+	r := slog.NewRecord(b.time, b.level, b.msg, 0)
+	if b.attrs != nil {
+		if b.group != "" {
+			r.AddAttrs(slog.Attr{Key: b.group, Value: slog.GroupValue(slices.Clone(*b.attrs)...)})
+		} else {
+			r.AddAttrs(*b.attrs...)
+		}
+		clear(*b.attrs)
+		*b.attrs = (*b.attrs)[:0]
+		attrPool.Put(b.attrs)
+		b.attrs = nil
+	}
+	return r
+}
+
+// FieldValue returns the value of the first attribute of r keyed by f. Fields renamed with
+// WithFieldNames are looked up under their default key, so look the new key up instead.
+func FieldValue(r slog.Record, f Field) (slog.Value, bool) {
+	var v slog.Value
+	found := false
+	r.Attrs(func(a slog.Attr) bool {
+		if a.Key == string(f) {
+			v, found = a.Value, true
+			return false
+		}
+		return true
+	})
+	return v, found
+}
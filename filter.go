@@ -0,0 +1,102 @@
+package slog
+
+import (
+	"regexp"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Filter decides, once the request has been handled, whether it is logged. It
+receives the latency of the request, so post-hoc criteria such as the status
+or the duration can be used. Filters compose with And, Or and Not:
+
+	slog.WithFilters(
+		slog.Not(slog.RouteFilter("/healthz", "/metrics")),
+		slog.Or(slog.StatusFilter(400, 599), slog.LatencyFilter(time.Second)),
+	)
+*/
+type Filter func(c *gin.Context, latency time.Duration) bool
+
+// And returns a filter passing the requests every filter passes.
+func And(filters ...Filter) Filter {
+	return func(c *gin.Context, latency time.Duration) bool {
+		for _, f := range filters {
+			if !f(c, latency) {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+// Or returns a filter passing the requests any filter passes.
+func Or(filters ...Filter) Filter {
+	return func(c *gin.Context, latency time.Duration) bool {
+		for _, f := range filters {
+			if f(c, latency) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// Not returns a filter passing the requests f does not.
+func Not(f Filter) Filter {
+	return func(c *gin.Context, latency time.Duration) bool {
+		return !f(c, latency)
+	}
+}
+
+// PathFilter passes the requests for one of the URL paths.
+func PathFilter(paths ...string) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		return slices.Contains(paths, c.Request.URL.Path)
+	}
+}
+
+// PathRegexpFilter passes the requests whose URL path matches re.
+func PathRegexpFilter(re *regexp.Regexp) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		return re.MatchString(c.Request.URL.Path)
+	}
+}
+
+// RouteFilter passes the requests matching one of the route templates, e.g. "/users/:id".
+func RouteFilter(routes ...string) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		return slices.Contains(routes, c.FullPath())
+	}
+}
+
+// MethodFilter passes the requests with one of the methods.
+func MethodFilter(methods ...string) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		return slices.Contains(methods, c.Request.Method)
+	}
+}
+
+// StatusFilter passes the requests with a status between minStatus and maxStatus, inclusive.
+func StatusFilter(minStatus, maxStatus int) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		status := c.Writer.Status()
+		return status >= minStatus && status <= maxStatus
+	}
+}
+
+// LatencyFilter passes the requests that took at least d.
+func LatencyFilter(d time.Duration) Filter {
+	return func(_ *gin.Context, latency time.Duration) bool {
+		return latency >= d
+	}
+}
+
+// HeaderFilter passes the requests with a name request header value matching re.
+func HeaderFilter(name string, re *regexp.Regexp) Filter {
+	return func(c *gin.Context, _ time.Duration) bool {
+		return slices.ContainsFunc(c.Request.Header.Values(name), re.MatchString)
+	}
+}
@@ -0,0 +1,40 @@
+package slog
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+)
+
+// forceLog holds the trusted header that bypasses sampling and skip rules.
+type forceLog struct {
+	header   string
+	secret   string
+	cidrs    []string
+	prefixes []netip.Prefix
+}
+
+func (f *forceLog) init() error {
+	if f.secret == "" && len(f.cidrs) == 0 {
+		return errors.New("force log header " + f.header + " requires a secret or trusted CIDRs")
+	}
+	var err error
+	f.prefixes, err = parsePrefixes(f.cidrs)
+	return err
+}
+
+// forced reports whether the request carries a valid force log header: its
+// value must match the secret, if any, and the client must be in a trusted
+// network, if any are configured.
+func (f *forceLog) forced(cfg *config, c *gin.Context) bool {
+	v := c.GetHeader(f.header)
+	if v == "" {
+		return false
+	}
+	if f.secret != "" && subtle.ConstantTimeCompare([]byte(v), []byte(f.secret)) != 1 {
+		return false
+	}
+	return len(f.prefixes) == 0 || containsAddr(f.prefixes, clientIP(cfg, c))
+}
@@ -0,0 +1,68 @@
+package slog
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// ecsFieldNames maps built-in fields to Elastic Common Schema fields.
+var ecsFieldNames = FieldNames{
+	FieldStatus:    "http.response.status_code",
+	FieldMethod:    "http.request.method",
+	FieldPath:      "url.path",
+	FieldQuery:     "url.query",
+	FieldURI:       "url.original",
+	FieldScheme:    "url.scheme",
+	FieldHost:      "url.domain",
+	FieldRoute:     "http.route",
+	FieldIP:        "client.ip",
+	FieldLatency:   "event.duration",
+	FieldReferer:   "http.request.referrer",
+	FieldUserAgent: "user_agent.original",
+	FieldBodySize:  "http.response.body.bytes",
+	FieldRequestID: "http.request.id",
+	FieldTraceID:   "trace.id",
+	FieldSpanID:    "span.id",
+}
+
+// otelFieldNames maps built-in fields to OpenTelemetry HTTP semantic convention attributes.
+var otelFieldNames = FieldNames{
+	FieldStatus:    "http.response.status_code",
+	FieldMethod:    "http.request.method",
+	FieldPath:      "url.path",
+	FieldQuery:     "url.query",
+	FieldScheme:    "url.scheme",
+	FieldRoute:     "http.route",
+	FieldIP:        "client.address",
+	FieldReferer:   "http.request.header.referer",
+	FieldUserAgent: "user_agent.original",
+	FieldBodySize:  "http.response.body.size",
+}
+
+// otelServerAttrs returns the server.address, server.port and
+// network.protocol.version attributes of r.
+func otelServerAttrs(r *http.Request) []slog.Attr {
+	host, port, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host, port = r.Host, ""
+	}
+	attrs := []slog.Attr{slog.String("server.address", host)}
+	if p, err := strconv.Atoi(port); err == nil {
+		attrs = append(attrs, slog.Int("server.port", p))
+	}
+	return append(attrs, slog.String("network.protocol.version", strconv.Itoa(r.ProtoMajor)+"."+strconv.Itoa(r.ProtoMinor)))
+}
+
+// requestScheme returns the scheme of r: https for TLS connections, the
+// X-Forwarded-Proto set by a proxy terminating TLS otherwise.
+func requestScheme(r *http.Request) string {
+	if r.TLS != nil {
+		return "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		return proto
+	}
+	return "http"
+}
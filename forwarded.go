@@ -0,0 +1,208 @@
+package slog
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+ForwardedChain returns the proxy chain of the request, client first: the for=
+nodes of the RFC 7239 Forwarded header when present, the X-Forwarded-For
+entries otherwise. Ports and IPv6 brackets are stripped; obfuscated and
+"unknown" nodes are kept as is. The chain is returned as sent, whoever added
+it; with WithTrustedProxies, the middleware only logs the nodes appended by
+trusted proxies.
+*/
+func ForwardedChain(r *http.Request) []string {
+	var chain []string
+	if values := r.Header.Values("Forwarded"); len(values) > 0 {
+		for _, v := range values {
+			chain = appendForwardedFor(chain, v)
+		}
+		return chain
+	}
+	for _, v := range r.Header.Values("X-Forwarded-For") {
+		for hop := range strings.SplitSeq(v, ",") {
+			if hop = strings.TrimSpace(hop); hop != "" {
+				chain = append(chain, hop)
+			}
+		}
+	}
+	return chain
+}
+
+// trustedFrom returns the index of the first node of a proxy chain of n nodes,
+// client first, appended by a trusted proxy. Walking from the peer, a node is
+// trusted when the proxy that appended it, the next node or the peer for the
+// last one, is trusted; the nodes before the first untrusted proxy may be
+// spoofed. Without WithTrustedProxies, every node is trusted.
+func (cfg *config) trustedFrom(c *gin.Context, n int, node func(i int) string) int {
+	if cfg.trustedPrefixes == nil {
+		return 0
+	}
+	proxy := remoteIP(c)
+	for i := n - 1; i >= 0; i-- {
+		if !containsAddr(cfg.trustedPrefixes, proxy) {
+			return i + 1
+		}
+		proxy = node(i)
+	}
+	return 0
+}
+
+// appendForwardedFor appends the for= nodes of a Forwarded header value.
+func appendForwardedFor(chain []string, v string) []string {
+	for _, hop := range appendProxyHops(nil, v) {
+		if hop.For != "" {
+			chain = append(chain, hop.For)
+		}
+	}
+	return chain
+}
+
+// ProxyHop is one element of the RFC 7239 Forwarded header: the client of a
+// proxy (for=), the proxy interface that received the request (by=), and the
+// Host header and protocol of the request it received.
+type ProxyHop struct {
+	For   string `json:"for,omitempty"`
+	By    string `json:"by,omitempty"`
+	Host  string `json:"host,omitempty"`
+	Proto string `json:"proto,omitempty"`
+}
+
+// attr returns the hop as a group of its set fields.
+func (h ProxyHop) attr(key string) slog.Attr {
+	var attrs []slog.Attr
+	for _, f := range [...]struct{ k, v string }{{"for", h.For}, {"by", h.By}, {"host", h.Host}, {"proto", h.Proto}} {
+		if f.v != "" {
+			attrs = append(attrs, slog.String(f.k, f.v))
+		}
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}
+}
+
+/*
+ProxyHops returns the proxy chain of the request, client first, with the
+for=, by=, host= and proto= parameters of each element of the RFC 7239
+Forwarded header. Without a Forwarded header, it is built from the
+X-Forwarded-For entries, the first of which is given the X-Forwarded-Host
+and X-Forwarded-Proto of the original request. Nodes are stripped like
+ForwardedChain's.
+*/
+func ProxyHops(r *http.Request) []ProxyHop {
+	var hops []ProxyHop
+	if values := r.Header.Values("Forwarded"); len(values) > 0 {
+		for _, v := range values {
+			hops = appendProxyHops(hops, v)
+		}
+		return hops
+	}
+	for _, node := range ForwardedChain(r) {
+		hops = append(hops, ProxyHop{For: node})
+	}
+	host, proto := firstValue(r.Header.Get("X-Forwarded-Host")), firstValue(r.Header.Get("X-Forwarded-Proto"))
+	if len(hops) == 0 && (host != "" || proto != "") {
+		hops = append(hops, ProxyHop{})
+	}
+	if len(hops) > 0 {
+		hops[0].Host, hops[0].Proto = host, proto
+	}
+	return hops
+}
+
+// firstValue returns the first entry of a comma-separated header value.
+func firstValue(v string) string {
+	first, _, _ := strings.Cut(v, ",")
+	return strings.TrimSpace(first)
+}
+
+// appendProxyHops appends the elements of a Forwarded header value. Quoted
+// values may contain the separators.
+func appendProxyHops(hops []ProxyHop, v string) []ProxyHop {
+	for _, element := range splitUnquoted(v, ',') {
+		var hop ProxyHop
+		for _, pair := range splitUnquoted(element, ';') {
+			key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+			if !ok {
+				continue
+			}
+			switch strings.ToLower(key) {
+			case "for":
+				hop.For = forwardedNode(value)
+			case "by":
+				hop.By = forwardedNode(value)
+			case "host":
+				hop.Host = unquote(value)
+			case "proto":
+				hop.Proto = strings.ToLower(unquote(value))
+			}
+		}
+		if hop != (ProxyHop{}) {
+			hops = append(hops, hop)
+		}
+	}
+	return hops
+}
+
+// splitUnquoted splits s at the occurrences of sep outside double quotes.
+func splitUnquoted(s string, sep byte) []string {
+	var parts []string
+	quoted, escaped, start := false, false, 0
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case escaped:
+			escaped = false
+		case c == '\\' && quoted:
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case c == sep && !quoted:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote returns a value without its surrounding quotes and escapes.
+func unquote(v string) string {
+	v = strings.TrimSpace(v)
+	if len(v) < 2 || v[0] != '"' || v[len(v)-1] != '"' {
+		return v
+	}
+	if u, err := strconv.Unquote(v); err == nil {
+		return u
+	}
+	return v[1 : len(v)-1]
+}
+
+// forwardedNode strips the quotes, port and IPv6 brackets of a node, e.g.
+// "[2001:db8::1]:4711" becomes 2001:db8::1.
+func forwardedNode(v string) string {
+	v = unquote(v)
+	if host, _, err := net.SplitHostPort(v); err == nil {
+		return host
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(v, "["), "]")
+}
+
+/*
+ForwardedClientIP is a client IP resolver for WithClientIPResolver that reports
+the first for= node of the RFC 7239 Forwarded header, falling back to
+c.ClientIP() when the header is absent or its first node is not an address.
+Use it only behind proxies that overwrite the Forwarded header, as clients can
+set it freely.
+*/
+func ForwardedClientIP(c *gin.Context) string {
+	if v := c.Request.Header.Get("Forwarded"); v != "" {
+		if chain := appendForwardedFor(nil, v); len(chain) > 0 && net.ParseIP(chain[0]) != nil {
+			return chain[0]
+		}
+	}
+	return c.ClientIP()
+}
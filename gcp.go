@@ -0,0 +1,86 @@
+package slog
+
+import (
+	"log/slog"
+	"os"
+	"strconv"
+)
+
+// gcpSeverity maps a level to a Google Cloud Logging severity.
+func gcpSeverity(l slog.Level) string {
+	switch {
+	case l < slog.LevelInfo:
+		return "DEBUG"
+	case l < slog.LevelWarn:
+		return "INFO"
+	case l < slog.LevelError:
+		return "WARNING"
+	case l < LevelFatal:
+		return "ERROR"
+	default:
+		return "CRITICAL"
+	}
+}
+
+/*
+GCPReplaceAttr is a slog.HandlerOptions.ReplaceAttr function that renders the
+level as the Cloud Logging "severity" field and the message as "message". The
+middleware's built-in handler uses it with WithGCPFormat; pass it to JSON
+handlers supplied via WithHandler, e.g.
+
+	slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: ginslog.GCPReplaceAttr})
+*/
+func GCPReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			return slog.String("severity", gcpSeverity(lvl))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	}
+	return a
+}
+
+// gcpLayout renders the access record as a Cloud Logging httpRequest group and
+// trace fields. project qualifies trace ids as projects/<project>/traces/<id>.
+func gcpLayout(project string) layout {
+	return func(rec *AccessRecord) []slog.Attr {
+		url := rec.URI
+		if url == "" {
+			url = rec.Path
+			if rec.Query != "" {
+				url += "?" + rec.Query
+			}
+		}
+		attrs := []slog.Attr{slog.Group("httpRequest",
+			slog.String("requestMethod", rec.Method),
+			slog.String("requestUrl", url),
+			slog.Int("status", rec.Status),
+			slog.String("responseSize", strconv.Itoa(max(rec.BodySize, 0))),
+			slog.String("latency", strconv.FormatFloat(rec.Latency.Seconds(), 'f', 9, 64)+"s"),
+			slog.String("remoteIp", rec.IP),
+			slog.String("userAgent", rec.UserAgent),
+			slog.String("referer", rec.Referer),
+		)}
+		if rec.TraceID != "" {
+			trace := rec.TraceID
+			if project != "" {
+				trace = "projects/" + project + "/traces/" + trace
+			}
+			attrs = append(attrs, slog.String("logging.googleapis.com/trace", trace))
+			if rec.SpanID != "" {
+				attrs = append(attrs, slog.String("logging.googleapis.com/spanId", rec.SpanID))
+			}
+		}
+		return attrs
+	}
+}
+
+// gcpProject returns the Google Cloud project the process runs in, if known.
+func gcpProject() string {
+	return os.Getenv("GOOGLE_CLOUD_PROJECT")
+}
@@ -0,0 +1,31 @@
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GeoIPFunc returns enrichment attributes (country, ASN, ...) of a client IP,
+// or nil when it knows nothing about it.
+type GeoIPFunc func(ip string) []slog.Attr
+
+// geoKey holds the per-request cache of GeoIPFunc results, keyed by IP.
+const geoKey = "_gin-contrib/slog_geo_"
+
+// geoAttrs returns the enrichment attributes of ip, looking each IP up at most
+// once per request, even across nested middleware instances.
+func geoAttrs(c *gin.Context, lookup GeoIPFunc, ip string) []slog.Attr {
+	v, _ := c.Get(geoKey)
+	cache, _ := v.(map[string][]slog.Attr)
+	if attrs, ok := cache[ip]; ok {
+		return attrs
+	}
+	if cache == nil {
+		cache = map[string][]slog.Attr{}
+		c.Set(geoKey, cache)
+	}
+	attrs := lookup(ip)
+	cache[ip] = attrs
+	return attrs
+}
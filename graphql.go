@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxGraphQLPeek bounds the request body bytes read to find the GraphQL operation.
+const maxGraphQLPeek = 64 << 10
+
+// graphqlOp is the operation of a GraphQL request.
+type graphqlOp struct {
+	name string // operationName, or the name in the document
+	typ  string // query, mutation or subscription
+}
+
+// peekGraphQL returns the operation of a GraphQL request, read from the query
+// parameters of GET requests and from the first bytes of the JSON body of POST
+// requests. The body is restored for the handlers.
+func peekGraphQL(c *gin.Context) (graphqlOp, bool) {
+	var req struct {
+		Query         string `json:"query"`
+		OperationName string `json:"operationName"`
+	}
+	switch c.Request.Method {
+	case http.MethodGet:
+		req.Query, req.OperationName = c.Query("query"), c.Query("operationName")
+	case http.MethodPost:
+		if c.Request.Body == nil || c.Request.Body == http.NoBody {
+			return graphqlOp{}, false
+		}
+		peek, err := io.ReadAll(io.LimitReader(c.Request.Body, maxGraphQLPeek+1))
+		c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(peek), c.Request.Body), Closer: c.Request.Body}
+		if err != nil || len(peek) > maxGraphQLPeek || json.Unmarshal(peek, &req) != nil {
+			return graphqlOp{}, false
+		}
+	default:
+		return graphqlOp{}, false
+	}
+	if req.Query == "" {
+		return graphqlOp{}, false
+	}
+	typ, name := parseGraphQLOperation(req.Query)
+	if req.OperationName != "" {
+		name = req.OperationName
+	}
+	return graphqlOp{name: name, typ: typ}, true
+}
+
+// parseGraphQLOperation returns the type and name of the first operation of a
+// GraphQL document. Shorthand documents ({ ... }) are anonymous queries.
+func parseGraphQLOperation(doc string) (typ, name string) {
+	for {
+		doc = strings.TrimLeftFunc(doc, func(r rune) bool { return unicode.IsSpace(r) || r == ',' })
+		if !strings.HasPrefix(doc, "#") {
+			break
+		}
+		_, doc, _ = strings.Cut(doc, "\n")
+	}
+	if strings.HasPrefix(doc, "{") {
+		return "query", ""
+	}
+	fields := strings.FieldsFunc(doc, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	})
+	if len(fields) == 0 {
+		return "", ""
+	}
+	switch fields[0] {
+	case "query", "mutation", "subscription":
+		typ = fields[0]
+	default:
+		return "", ""
+	}
+	rest := strings.TrimSpace(doc[len(typ):])
+	if len(fields) > 1 && strings.HasPrefix(rest, fields[1]) {
+		name = fields[1]
+	}
+	return typ, name
+}
+
+// readCloser combines a Reader with the Closer of the original body.
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
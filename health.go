@@ -0,0 +1,43 @@
+package slog
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// probePaths are the paths commonly served to health check probes.
+var probePaths = map[string]struct{}{
+	"/healthz": {},
+	"/livez":   {},
+	"/readyz":  {},
+	"/ping":    {},
+}
+
+// probeUserAgents are User-Agent prefixes of common health check probes.
+var probeUserAgents = []string{
+	"kube-probe/",
+	"ELB-HealthChecker/",
+	"GoogleHC/",
+	"Consul Health Check",
+}
+
+// isProbe reports whether the request looks like a health check probe.
+func isProbe(c *gin.Context) bool {
+	if _, ok := probePaths[c.Request.URL.Path]; ok {
+		return true
+	}
+	ua := c.Request.UserAgent()
+	for _, prefix := range probeUserAgents {
+		if strings.HasPrefix(ua, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeSkipper skips successful health check probes.
+func probeSkipper(c *gin.Context) bool {
+	return c.Writer.Status() < http.StatusBadRequest && isProbe(c)
+}
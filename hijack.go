@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"bufio"
+	"net"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// hijackWriter wraps gin.ResponseWriter so that bytes written to a hijacked
+// connection, e.g. a websocket, are still reported as the body size.
+type hijackWriter struct {
+	gin.ResponseWriter
+	hijacked atomic.Bool
+	written  atomic.Int64
+}
+
+// Hijack implements http.Hijacker.
+func (w *hijackWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	conn, brw, err := w.ResponseWriter.Hijack()
+	if err != nil {
+		return conn, brw, err
+	}
+	w.hijacked.Store(true)
+	cc := &countingConn{Conn: conn, n: &w.written}
+	if brw != nil && brw.Writer.Buffered() == 0 {
+		brw.Writer = bufio.NewWriter(cc)
+	}
+	return cc, brw, nil
+}
+
+// Size returns the bytes written to the hijacked connection so far once the
+// connection is hijacked, and the response body size otherwise.
+func (w *hijackWriter) Size() int {
+	if w.hijacked.Load() {
+		return int(w.written.Load())
+	}
+	return w.ResponseWriter.Size()
+}
+
+// countingConn counts the bytes written to a hijacked connection.
+type countingConn struct {
+	net.Conn
+	n *atomic.Int64
+}
+
+// Write implements io.Writer.
+func (c *countingConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	c.n.Add(int64(n))
+	return n, err
+}
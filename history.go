@@ -0,0 +1,103 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestHistory keeps the last logged records, encoded as JSON, in a ring buffer.
+type requestHistory struct {
+	mu      sync.Mutex
+	records []json.RawMessage
+	next    int  // index of the next record to overwrite
+	full    bool // records wrapped around
+}
+
+func newRequestHistory(n int) *requestHistory {
+	return &requestHistory{records: make([]json.RawMessage, n)}
+}
+
+// add records an encoded record, evicting the oldest once full.
+func (h *requestHistory) add(rec json.RawMessage) {
+	h.mu.Lock()
+	h.records[h.next] = rec
+	if h.next++; h.next == len(h.records) {
+		h.next, h.full = 0, true
+	}
+	h.mu.Unlock()
+}
+
+// recent returns the records, newest first.
+func (h *requestHistory) recent() []json.RawMessage {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := h.next
+	if h.full {
+		n = len(h.records)
+	}
+	out := make([]json.RawMessage, 0, n)
+	for i := range n {
+		out = append(out, h.records[(h.next-1-i+len(h.records))%len(h.records)])
+	}
+	return out
+}
+
+// addHistory encodes a logged record, already redacted, as the JSON handler
+// would write it, with the request id, tenant and trace ids of the request
+// logger, and the encrypted fields encrypted.
+func (cfg *config) addHistory(access *AccessRecord, record slog.Record) {
+	var buf bytes.Buffer
+	var h slog.Handler = slog.NewJSONHandler(&buf, cfg.builtinHandlerOptions(ReplaceLevelNames))
+	if len(cfg.encryptedFields) > 0 {
+		h = &encryptHandler{next: h, fields: cfg.encryptedFields, keys: cfg.keyProvider}
+	}
+	var attrs []slog.Attr
+	if access.TraceID != "" {
+		attrs = append(attrs, slog.String(cfg.fieldNames.key(FieldTraceID), access.TraceID))
+		if access.SpanID != "" {
+			attrs = append(attrs, slog.String(cfg.fieldNames.key(FieldSpanID), access.SpanID))
+		}
+	}
+	if access.RequestID != "" {
+		attrs = append(attrs, slog.String(cfg.fieldNames.key(FieldRequestID), access.RequestID))
+	}
+	if access.Tenant != "" {
+		attrs = append(attrs, slog.String(cfg.fieldNames.key(FieldTenant), access.Tenant))
+	}
+	if cfg.redactor != nil {
+		attrs = cfg.redactor.attrs(attrs)
+	}
+	if err := h.WithAttrs(attrs).Handle(context.Background(), record); err != nil {
+		return
+	}
+	cfg.history.add(bytes.TrimSpace(buf.Bytes()))
+}
+
+/*
+HistoryHandler returns a debug endpoint serving, as a JSON array newest first,
+the records kept by WithRequestHistory, for when the log pipeline is down or
+delayed. It serves an empty array without WithRequestHistory.
+
+	m, _ := slog.New(slog.WithRequestHistory(500))
+	r.Use(m.Handler())
+	admin.GET("/debug/requests", m.HistoryHandler())
+
+The records are those passed to the hooks, with WithRedaction and
+WithEncryptedFields applied, but still hold the paths and client IPs of the
+requests: the endpoint should only be reachable by operators.
+*/
+func (m *Middleware) HistoryHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		records := []json.RawMessage{}
+		if h := m.cfg.Load().history; h != nil {
+			records = h.recent()
+		}
+		c.JSON(http.StatusOK, records)
+	}
+}
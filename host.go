@@ -0,0 +1,23 @@
+package slog
+
+import (
+	"log/slog"
+	"os"
+)
+
+// hostAttrs returns the hostname, pid and, when set in the environment, the
+// pod (POD_NAME) and container (HOSTNAME) names of the process.
+func hostAttrs() []slog.Attr {
+	attrs := make([]slog.Attr, 0, 4)
+	if hostname, err := os.Hostname(); err == nil {
+		attrs = append(attrs, slog.String("hostname", hostname))
+	}
+	attrs = append(attrs, slog.Int("pid", os.Getpid()))
+	if pod := os.Getenv("POD_NAME"); pod != "" {
+		attrs = append(attrs, slog.String("pod", pod))
+	}
+	if container := os.Getenv("HOSTNAME"); container != "" {
+		attrs = append(attrs, slog.String("container", container))
+	}
+	return attrs
+}
@@ -0,0 +1,141 @@
+package slog
+
+import (
+	"cmp"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/netip"
+	"slices"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cidrLevel maps a network prefix to a log level.
+type cidrLevel struct {
+	prefix netip.Prefix
+	level  slog.Level
+}
+
+// parsePrefix parses a CIDR such as "10.0.0.0/8" or a bare IP address.
+func parsePrefix(s string) (netip.Prefix, error) {
+	if !strings.Contains(s, "/") {
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		addr = addr.Unmap()
+		return netip.PrefixFrom(addr, addr.BitLen()), nil
+	}
+	p, err := netip.ParsePrefix(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return netip.PrefixFrom(p.Addr().Unmap(), p.Bits()).Masked(), nil
+}
+
+// parseCIDRLevels parses the CIDR level map, most specific prefixes first.
+func parseCIDRLevels(m map[string]slog.Level) ([]cidrLevel, error) {
+	levels := make([]cidrLevel, 0, len(m))
+	for s, lvl := range m {
+		p, err := parsePrefix(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", s, err)
+		}
+		levels = append(levels, cidrLevel{prefix: p, level: lvl})
+	}
+	slices.SortFunc(levels, func(a, b cidrLevel) int {
+		return cmp.Compare(b.prefix.Bits(), a.prefix.Bits())
+	})
+	return levels, nil
+}
+
+// levelForIP returns the level of the most specific prefix containing ip.
+func levelForIP(levels []cidrLevel, ip string) (slog.Level, bool) {
+	if len(levels) == 0 {
+		return 0, false
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return 0, false
+	}
+	addr = addr.Unmap()
+	for _, l := range levels {
+		if l.prefix.Contains(addr) {
+			return l.level, true
+		}
+	}
+	return 0, false
+}
+
+// parsePrefixes parses a list of CIDRs or bare IP addresses.
+func parsePrefixes(list []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(list))
+	for _, s := range list {
+		p, err := parsePrefix(strings.TrimSpace(s))
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", s, err)
+		}
+		prefixes = append(prefixes, p)
+	}
+	return prefixes, nil
+}
+
+// containsAddr reports whether any prefix contains the given address string.
+func containsAddr(prefixes []netip.Prefix, ip string) bool {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteIP returns the IP part of the request's RemoteAddr.
+func remoteIP(c *gin.Context) string {
+	host, _, err := net.SplitHostPort(strings.TrimSpace(c.Request.RemoteAddr))
+	if err != nil {
+		return strings.TrimSpace(c.Request.RemoteAddr)
+	}
+	return host
+}
+
+/*
+clientIP returns the client IP address for the log record, as reported by the
+WithClientIPResolver function when set. Without a trust
+policy it defers to c.ClientIP() and gin's engine settings. With trusted
+proxies configured, X-Forwarded-For is only honored when the peer is trusted,
+and the chain is walked right to left until the first untrusted hop, so
+spoofed entries prepended by the client are never reported.
+*/
+func clientIP(cfg *config, c *gin.Context) string {
+	if cfg.ipResolver != nil {
+		return cfg.ipResolver(c)
+	}
+	if cfg.trustedPrefixes == nil {
+		return c.ClientIP()
+	}
+	ip := remoteIP(c)
+	if !containsAddr(cfg.trustedPrefixes, ip) {
+		return ip
+	}
+	// Proxies may add their hop on a header line of their own.
+	hops := strings.Split(strings.Join(c.Request.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(hops[i])
+		if _, err := netip.ParseAddr(hop); err != nil {
+			break
+		}
+		ip = hop
+		if !containsAddr(cfg.trustedPrefixes, hop) {
+			break
+		}
+	}
+	return ip
+}
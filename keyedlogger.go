@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxKeyedLoggers bounds the number of loggers cached by WithKeyedLogger.
+const maxKeyedLoggers = 1024
+
+// keyedLoggerKey identifies a cached logger by the logger it derives from and its key.
+type keyedLoggerKey struct {
+	parent *slog.Logger
+	key    string
+}
+
+/*
+keyedLoggers caches the request loggers derived per key, so requests sharing a
+key (a tier, a client, an API version, ...) reuse one derived logger instead of
+allocating a logger and its handler state each. Past maxKeyedLoggers keys,
+loggers are derived per request again.
+*/
+type keyedLoggers struct {
+	key     func(*gin.Context) string
+	derive  func(key string, l *slog.Logger) *slog.Logger
+	loggers sync.Map // keyedLoggerKey -> *slog.Logger
+	n       atomic.Int64
+}
+
+// get returns the logger derived from parent for the key of the request.
+func (k *keyedLoggers) get(c *gin.Context, parent *slog.Logger) *slog.Logger {
+	key := k.key(c)
+	ck := keyedLoggerKey{parent: parent, key: key}
+	if l, ok := k.loggers.Load(ck); ok {
+		return l.(*slog.Logger)
+	}
+	l := k.derive(key, parent)
+	if k.n.Load() < maxKeyedLoggers {
+		if actual, loaded := k.loggers.LoadOrStore(ck, l); loaded {
+			return actual.(*slog.Logger)
+		}
+		k.n.Add(1)
+	}
+	return l
+}
@@ -0,0 +1,72 @@
+package slog
+
+import (
+	"log/slog"
+	"time"
+)
+
+// latencyBucketLabels returns one label per bucket delimited by the given
+// sorted bounds, e.g. [100ms 1s] yields "<100ms", "100ms-1s" and ">1s".
+func latencyBucketLabels(bounds []time.Duration) []string {
+	if len(bounds) == 0 {
+		return nil
+	}
+	labels := make([]string, 0, len(bounds)+1)
+	labels = append(labels, "<"+bounds[0].String())
+	for i := 1; i < len(bounds); i++ {
+		labels = append(labels, bounds[i-1].String()+"-"+bounds[i].String())
+	}
+	return append(labels, ">"+bounds[len(bounds)-1].String())
+}
+
+// latencyBucket returns the index of the bucket the latency falls into.
+func latencyBucket(bounds []time.Duration, latency time.Duration) int {
+	for i, b := range bounds {
+		if latency < b {
+			return i
+		}
+	}
+	return len(bounds)
+}
+
+// LatencyFormat is the rendering of the latency attribute.
+type LatencyFormat int
+
+const (
+	// LatencyDuration logs a time.Duration, rendered by the handler (default).
+	LatencyDuration LatencyFormat = iota
+	// LatencyMilliseconds logs fractional milliseconds as a float.
+	LatencyMilliseconds
+	// LatencyNanoseconds logs integer nanoseconds.
+	LatencyNanoseconds
+)
+
+// value returns d in the format.
+func (f LatencyFormat) value(d time.Duration) slog.Value {
+	switch f {
+	case LatencyMilliseconds:
+		return slog.Float64Value(float64(d) / float64(time.Millisecond))
+	case LatencyNanoseconds:
+		return slog.Int64Value(d.Nanoseconds())
+	case LatencyDuration:
+	}
+	return slog.DurationValue(d)
+}
+
+// replaceTime returns a ReplaceAttr function rendering the record time with
+// layout under key, wrapping next. Empty arguments keep the defaults.
+func replaceTime(layout, key string, next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	return func(groups []string, a slog.Attr) slog.Attr {
+		a = next(groups, a)
+		if len(groups) > 0 || a.Key != slog.TimeKey {
+			return a
+		}
+		if t, ok := a.Value.Any().(time.Time); ok && layout != "" {
+			a.Value = slog.StringValue(t.Format(layout))
+		}
+		if key != "" {
+			a.Key = key
+		}
+		return a
+	}
+}
@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"log/slog"
+	"strings"
+	"time"
+)
+
+/*
+legacyReplaceAttr renders records like gin-contrib/logger's zerolog output:
+a lower-case level, the message as "message" and the time in RFC 3339.
+*/
+func legacyReplaceAttr(groups []string, a slog.Attr) slog.Attr {
+	if len(groups) > 0 {
+		return a
+	}
+	switch a.Key {
+	case slog.LevelKey:
+		if lvl, ok := a.Value.Any().(slog.Level); ok {
+			a.Value = slog.StringValue(strings.ToLower(levelName(lvl)))
+		}
+	case slog.MessageKey:
+		a.Key = "message"
+	case slog.TimeKey:
+		if t, ok := a.Value.Any().(time.Time); ok {
+			a.Value = slog.StringValue(t.Format(time.RFC3339))
+		}
+	}
+	return a
+}
+
+// legacyLayout renders the request fields like gin-contrib/logger: the path
+// including the query string and the latency in fractional milliseconds.
+func legacyLayout(rec *AccessRecord) []slog.Attr {
+	path := rec.Path
+	if rec.Query != "" {
+		path += "?" + rec.Query
+	}
+	return []slog.Attr{
+		slog.Int("status", rec.Status),
+		slog.String("method", rec.Method),
+		slog.String("path", path),
+		slog.String("ip", rec.IP),
+		slog.Float64("latency", float64(rec.Latency)/float64(time.Millisecond)),
+		slog.String("user_agent", rec.UserAgent),
+		slog.Int("body_size", rec.BodySize),
+	}
+}
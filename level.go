@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// LevelTrace is a level more verbose than slog.LevelDebug.
+	LevelTrace = slog.Level(-8)
+	// LevelFatal is a level more severe than slog.LevelError.
+	LevelFatal = slog.Level(12)
+)
+
+// levelName renders a level like slog.Level.String, naming LevelTrace and
+// LevelFatal (and offsets from them) instead of "DEBUG-4" and "ERROR+4".
+func levelName(l slog.Level) string {
+	str := func(base string, val slog.Level) string {
+		if val == 0 {
+			return base
+		}
+		return fmt.Sprintf("%s%+d", base, val)
+	}
+	switch {
+	case l < slog.LevelDebug:
+		return str("TRACE", l-LevelTrace)
+	case l >= LevelFatal:
+		return str("FATAL", l-LevelFatal)
+	default:
+		return l.String()
+	}
+}
+
+/*
+ReplaceLevelNames is a slog.HandlerOptions.ReplaceAttr function that renders
+LevelTrace and LevelFatal as "TRACE" and "FATAL". The middleware's built-in
+handler uses it; pass it to handlers supplied via WithHandler, e.g.
+
+	slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{ReplaceAttr: ginslog.ReplaceLevelNames})
+*/
+func ReplaceLevelNames(groups []string, a slog.Attr) slog.Attr {
+	if a.Key != slog.LevelKey || len(groups) > 0 {
+		return a
+	}
+	if lvl, ok := a.Value.Any().(slog.Level); ok {
+		a.Value = slog.StringValue(levelName(lvl))
+	}
+	return a
+}
+
+// startupLeveler reports a startup level until a deadline and the base level afterwards.
+type startupLeveler struct {
+	base    slog.Leveler
+	startup slog.Level
+	until   time.Time
+}
+
+// Level implements slog.Leveler.
+func (l startupLeveler) Level() slog.Level {
+	if time.Now().Before(l.until) {
+		return l.startup
+	}
+	return l.base.Level()
+}
@@ -0,0 +1,125 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// levelFileInterval is how often a level config file is checked for changes.
+const levelFileInterval = 5 * time.Second
+
+// levelFileContent is the YAML (or JSON) content of a level config file.
+type levelFileContent struct {
+	Paths  map[string]string `yaml:"paths"`
+	Routes map[string]string `yaml:"routes"`
+	Status map[string]string `yaml:"status"`
+}
+
+// fileLevels are the parsed levels of a level config file.
+type fileLevels struct {
+	paths  map[string]slog.Level
+	routes map[string]slog.Level
+	status map[int]slog.Level
+}
+
+// noFileLevels is the empty fileLevels used without a level config file.
+var noFileLevels fileLevels
+
+/*
+levelFile holds the levels of a level config file. Requests check the file for
+changes at most once per levelFileInterval, without a watcher goroutine; a
+file that fails to load keeps the previous levels in effect.
+*/
+type levelFile struct {
+	path   string
+	levels atomic.Pointer[fileLevels]
+	next   atomic.Int64 // UnixNano of the next check
+
+	mu      sync.Mutex // held by the request checking the file
+	modTime time.Time
+	size    int64
+}
+
+// load reads and parses the file when it changed since the last load.
+func (f *levelFile) load() error {
+	fi, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	if f.levels.Load() != nil && fi.ModTime().Equal(f.modTime) && fi.Size() == f.size {
+		return nil
+	}
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	levels, err := parseLevelFile(data)
+	if err != nil {
+		return fmt.Errorf("level config file %s: %w", f.path, err)
+	}
+	f.levels.Store(levels)
+	f.modTime, f.size = fi.ModTime(), fi.Size()
+	return nil
+}
+
+// current returns the levels in effect, checking the file for changes when
+// due. Reload failures are logged through l.
+func (f *levelFile) current(now time.Time, l *slog.Logger) *fileLevels {
+	if f == nil {
+		return &noFileLevels
+	}
+	if now.UnixNano() >= f.next.Load() && f.mu.TryLock() {
+		f.next.Store(now.Add(levelFileInterval).UnixNano())
+		if err := f.load(); err != nil {
+			l.Warn("Level config reload failed", slog.String("path", f.path), slog.String("error", err.Error()))
+		}
+		f.mu.Unlock()
+	}
+	return f.levels.Load()
+}
+
+// parseLevelFile parses the content of a level config file.
+func parseLevelFile(data []byte) (*fileLevels, error) {
+	var content levelFileContent
+	if err := yaml.Unmarshal(data, &content); err != nil {
+		return nil, err
+	}
+	levels := &fileLevels{status: make(map[int]slog.Level, len(content.Status))}
+	var err error
+	if levels.paths, err = parseLevelMap(content.Paths); err != nil {
+		return nil, err
+	}
+	if levels.routes, err = parseLevelMap(content.Routes); err != nil {
+		return nil, err
+	}
+	for code, name := range content.Status {
+		status, err := strconv.Atoi(code)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %q", code)
+		}
+		if levels.status[status], err = parseLevelText(name); err != nil {
+			return nil, err
+		}
+	}
+	return levels, nil
+}
+
+// parseLevelMap parses the level names of m.
+func parseLevelMap(m map[string]string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level, len(m))
+	for k, name := range m {
+		lvl, err := parseLevelText(name)
+		if err != nil {
+			return nil, err
+		}
+		levels[k] = lvl
+	}
+	return levels, nil
+}
@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Levels holds the runtime-adjustable levels of a middleware.
+type Levels struct {
+	Default     *slog.LevelVar // <400 log level
+	ClientError *slog.LevelVar // 400-499 log level
+	ServerError *slog.LevelVar // >=500 log level
+}
+
+// NewLevels returns levels initialized to Info, Warn and Error.
+func NewLevels() *Levels {
+	l := &Levels{Default: &slog.LevelVar{}, ClientError: &slog.LevelVar{}, ServerError: &slog.LevelVar{}}
+	l.ClientError.Set(slog.LevelWarn)
+	l.ServerError.Set(slog.LevelError)
+	return l
+}
+
+// vars returns the level variables keyed by their LevelHandler JSON name.
+func (l *Levels) vars() map[string]*slog.LevelVar {
+	return map[string]*slog.LevelVar{
+		"default":      l.Default,
+		"client_error": l.ClientError,
+		"server_error": l.ServerError,
+	}
+}
+
+// parseLevelText parses names accepted by ParseLevel as well as slog's "DEBUG-2" form.
+func parseLevelText(s string) (slog.Level, error) {
+	if lvl, err := ParseLevel(strings.ToLower(s)); err == nil {
+		return lvl, nil
+	}
+	var lvl slog.Level
+	err := lvl.UnmarshalText([]byte(s))
+	return lvl, err
+}
+
+/*
+LevelHandler returns an admin endpoint for the levels of a middleware
+configured with WithLevels, similar to zap's AtomicLevel handler:
+  - GET returns {"default": "info", "client_error": "warn", "server_error": "error"}.
+  - PUT with the same JSON body (any subset of the keys) changes the levels.
+
+The endpoint should only be reachable by operators.
+*/
+func LevelHandler(levels *Levels) gin.HandlerFunc {
+	vars := levels.vars()
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var body map[string]string
+			if err := c.ShouldBindJSON(&body); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			parsed := make(map[string]slog.Level, len(body))
+			for name, text := range body {
+				if _, ok := vars[name]; !ok {
+					c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("unknown level key: %s", name)})
+					return
+				}
+				lvl, err := parseLevelText(text)
+				if err != nil {
+					c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+					return
+				}
+				parsed[name] = lvl
+			}
+			for name, lvl := range parsed {
+				vars[name].Set(lvl)
+			}
+		default:
+			c.AbortWithStatus(http.StatusMethodNotAllowed)
+			return
+		}
+
+		out := make(map[string]string, len(vars))
+		for name, v := range vars {
+			out[name] = strings.ToLower(levelName(v.Level()))
+		}
+		c.JSON(http.StatusOK, out)
+	}
+}
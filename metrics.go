@@ -0,0 +1,20 @@
+package slog
+
+import "time"
+
+/*
+MetricsRecorder records every request handled by a middleware configured with
+WithMetrics, including skipped and sampled out ones. The
+github.com/gin-contrib/slog/metrics package provides a Prometheus recorder.
+
+Parameters:
+
+	route - the route template or normalized route, empty for unmatched routes.
+	method - the request method.
+	status - the response status.
+	size - the response body size, -1 if nothing was written.
+	latency - the time taken to handle the request.
+*/
+type MetricsRecorder interface {
+	ObserveRequest(route, method string, status, size int, latency time.Duration)
+}
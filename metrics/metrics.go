@@ -0,0 +1,67 @@
+/*
+Package metrics exports the requests handled by the middleware as Prometheus
+metrics: http_requests_total, http_request_duration_seconds and
+http_response_size_bytes, labeled by route, method and status.
+
+A Recorder is a slog.MetricsRecorder, so it plugs into the middleware with
+WithMetrics, keeping the numbers consistent with the access logs:
+
+	rec, err := metrics.New(prometheus.DefaultRegisterer)
+	if err != nil {
+		return err
+	}
+	r.Use(ginslog.SetLogger(ginslog.WithMetrics(rec)))
+*/
+package metrics
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Recorder holds the Prometheus collectors fed by the middleware.
+type Recorder struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	size     *prometheus.HistogramVec
+}
+
+// New registers the request collectors with reg.
+func New(reg prometheus.Registerer) (*Recorder, error) {
+	labels := []string{"route", "method", "status"}
+	m := &Recorder{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total number of HTTP requests handled.",
+		}, labels),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "Time taken to handle HTTP requests.",
+			Buckets: prometheus.DefBuckets,
+		}, labels),
+		size: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_response_size_bytes",
+			Help:    "Size of HTTP response bodies.",
+			Buckets: prometheus.ExponentialBuckets(100, 10, 7),
+		}, labels),
+	}
+	for _, c := range []prometheus.Collector{m.requests, m.duration, m.size} {
+		if err := reg.Register(c); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// ObserveRequest implements slog.MetricsRecorder. Requests without a matched
+// route share an empty route label, keeping the label cardinality bounded.
+func (m *Recorder) ObserveRequest(route, method string, status, size int, latency time.Duration) {
+	code := strconv.Itoa(status)
+	m.requests.WithLabelValues(route, method, code).Inc()
+	m.duration.WithLabelValues(route, method, code).Observe(latency.Seconds())
+	if size >= 0 {
+		m.size.WithLabelValues(route, method, code).Observe(float64(size))
+	}
+}
@@ -0,0 +1,160 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Middleware is a request logger that can be reconfigured while serving, e.g.
+by ops tooling on a live process. Its mutators are safe for concurrent use:
+each swaps in an updated copy of the configuration, so a request is logged
+with the settings in effect when it started.
+
+	m, err := slog.New(slog.WithSkipPath([]string{"/healthz"}))
+	if err != nil {
+		log.Fatal(err)
+	}
+	r.Use(m.Handler())
+	...
+	m.SetLevel(slog.LevelDebug)
+*/
+type Middleware struct {
+	mu     sync.Mutex // serializes updates
+	cfg    atomic.Pointer[config]
+	levels *Levels
+}
+
+// New returns a Middleware configured by opts, or an error listing the invalid or contradictory
+// settings, such as a nil writer, a sample rate above 1 or a skipped path given a level.
+// Its levels are backed by WithLevels, or by level variables initialized from the level options.
+func New(opts ...Option) (*Middleware, error) {
+	cfg := newConfig(opts...)
+	if cfg.levels == nil {
+		cfg.levels = &Levels{Default: cfg.levelVar, ClientError: &slog.LevelVar{}, ServerError: &slog.LevelVar{}}
+		if cfg.levels.Default == nil {
+			cfg.levels.Default = &slog.LevelVar{}
+			cfg.levels.Default.Set(cfg.defaultLevel)
+		}
+		cfg.levels.ClientError.Set(cfg.clientErrorLevel)
+		cfg.levels.ServerError.Set(cfg.serverErrorLevel)
+	}
+	if err := cfg.init(); err != nil {
+		return nil, err
+	}
+	m := &Middleware{levels: cfg.levels}
+	m.cfg.Store(cfg)
+	return m, nil
+}
+
+// Handler returns the gin middleware logging requests with the current configuration.
+func (m *Middleware) Handler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.cfg.Load().handle(c)
+	}
+}
+
+// Levels returns the levels of the middleware, e.g. for LevelHandler.
+func (m *Middleware) Levels() *Levels {
+	return m.levels
+}
+
+// SetLevel sets the default level, used for requests with a status below 400.
+func (m *Middleware) SetLevel(level slog.Level) {
+	m.levels.Default.Set(level)
+}
+
+// SetSampler sets the function deciding whether a successful request is logged, like
+// WithSampler. A nil fn logs every request.
+func (m *Middleware) SetSampler(fn func(c *gin.Context) bool) {
+	m.update(func(cfg *config) {
+		cfg.sampler = fn
+	})
+}
+
+// AddSkipPath adds paths to skip logging for, like WithSkipPath.
+func (m *Middleware) AddSkipPath(paths ...string) {
+	m.update(func(cfg *config) {
+		cfg.skipPath = append(slices.Clip(cfg.skipPath), paths...)
+		cfg.skipSet = make(map[string]struct{}, len(cfg.skipPath))
+		for _, p := range cfg.skipPath {
+			cfg.skipSet[p] = struct{}{}
+		}
+	})
+}
+
+// Flush writes the output buffered by WithBatching, waiting at most until ctx is done.
+func (m *Middleware) Flush(ctx context.Context) error {
+	return untilDone(ctx, func() error {
+		if b := m.cfg.Load().batch; b != nil {
+			return b.Flush()
+		}
+		return nil
+	})
+}
+
+/*
+Close drains the middleware for server shutdown, waiting at most until ctx is
+done: it emits the pending route-not-found and dropped log summaries, writes
+the output buffered by WithBatching and closes the WithRotatingFile file.
+Requests logged after Close are written unbuffered, or lost with a rotating
+file, so call it once the server has stopped serving.
+
+	srv.Shutdown(ctx)
+	m.Close(ctx)
+*/
+func (m *Middleware) Close(ctx context.Context) error {
+	cfg := m.cfg.Load()
+	return untilDone(ctx, func() error {
+		now := time.Now()
+		if cfg.notFound != nil {
+			cfg.notFound.flush(cfg.base, cfg.clientErrorLeveler.Level(), now)
+		}
+		if cfg.logLimiter != nil {
+			cfg.logLimiter.flush(cfg.base, slog.LevelWarn, now)
+		}
+		var errs []error
+		if cfg.batch != nil {
+			errs = append(errs, cfg.batch.Close())
+		}
+		if cfg.rotating != nil {
+			errs = append(errs, cfg.rotating.Close())
+		}
+		return errors.Join(errs...)
+	})
+}
+
+// untilDone runs fn and returns its error, or ctx.Err() if ctx is done first.
+func untilDone(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// update swaps in a copy of the configuration, and of its route overrides,
+// changed by fn.
+func (m *Middleware) update(fn func(*config)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	nc := *m.cfg.Load()
+	fn(&nc)
+	nc.routeOverrides = slices.Clone(nc.routeOverrides)
+	for i := range nc.routeOverrides {
+		oc := *nc.routeOverrides[i].cfg
+		fn(&oc)
+		nc.routeOverrides[i].cfg = &oc
+	}
+	m.cfg.Store(&nc)
+}
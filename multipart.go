@@ -0,0 +1,131 @@
+package slog
+
+import (
+	"io"
+	"log/slog"
+	"mime"
+	"mime/multipart"
+	"strconv"
+)
+
+// maxMultipartParts bounds the number of parts described per request.
+const maxMultipartParts = 100
+
+// multipartPart is the logged metadata of a multipart/form-data part.
+type multipartPart struct {
+	name        string
+	filename    string
+	contentType string
+	size        int64
+}
+
+/*
+multipartTee feeds the first bytes of a multipart/form-data body read by the
+handlers to a parser goroutine recording the metadata of each part, so parts
+are described without buffering their contents. Bytes beyond the guard are
+not inspected.
+*/
+type multipartTee struct {
+	io.ReadCloser
+	pw        *io.PipeWriter
+	left      int64 // bytes still fed to the parser
+	truncated bool  // the guard was reached
+	done      chan struct{}
+
+	// set by the parser before done is closed
+	parts      []multipartPart
+	incomplete bool
+}
+
+// newMultipartTee returns a tee over body when contentType is multipart/form-data.
+func newMultipartTee(body io.ReadCloser, contentType string, maxBytes int64) *multipartTee {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/form-data" || params["boundary"] == "" {
+		return nil
+	}
+	pr, pw := io.Pipe()
+	t := &multipartTee{ReadCloser: body, pw: pw, left: maxBytes, done: make(chan struct{})}
+	go t.parse(multipart.NewReader(pr, params["boundary"]), pr)
+	return t
+}
+
+// Read implements io.Reader.
+func (t *multipartTee) Read(p []byte) (int, error) {
+	n, err := t.ReadCloser.Read(p)
+	if t.pw != nil && n > 0 {
+		feed := p[:min(int64(n), t.left)]
+		_, _ = t.pw.Write(feed)
+		if t.left -= int64(len(feed)); t.left == 0 {
+			t.truncated = true
+			t.stop()
+		}
+	}
+	if err != nil {
+		t.stop()
+	}
+	return n, err
+}
+
+// stop ends the input of the parser.
+func (t *multipartTee) stop() {
+	if t.pw != nil {
+		_ = t.pw.Close()
+		t.pw = nil
+	}
+}
+
+// parse records the parts read from mr, then drains the pipe so the tee never blocks.
+func (t *multipartTee) parse(mr *multipart.Reader, pr *io.PipeReader) {
+	defer close(t.done)
+	defer func() { _, _ = io.Copy(io.Discard, pr) }()
+	for len(t.parts) < maxMultipartParts {
+		part, err := mr.NextPart()
+		if err != nil {
+			t.incomplete = err != io.EOF
+			return
+		}
+		n, err := io.Copy(io.Discard, part)
+		t.parts = append(t.parts, multipartPart{
+			name:        part.FormName(),
+			filename:    part.FileName(),
+			contentType: part.Header.Get("Content-Type"),
+			size:        n,
+		})
+		if err != nil {
+			t.incomplete = true
+			return
+		}
+	}
+	t.incomplete = true
+}
+
+// finish stops the parser and waits for its result. It is safe to call more than once.
+func (t *multipartTee) finish() {
+	t.stop()
+	<-t.done
+}
+
+// attr returns the multipart group: one group per part, keyed by its index,
+// and truncated when not every part was described in full.
+func (t *multipartTee) attr(key string) (slog.Attr, bool) {
+	t.finish()
+	if len(t.parts) == 0 {
+		return slog.Attr{}, false
+	}
+	attrs := make([]slog.Attr, 0, len(t.parts)+1)
+	for i, p := range t.parts {
+		fields := []slog.Attr{slog.String("name", p.name)}
+		if p.filename != "" {
+			fields = append(fields, slog.String("filename", p.filename))
+		}
+		if p.contentType != "" {
+			fields = append(fields, slog.String("content_type", p.contentType))
+		}
+		fields = append(fields, slog.Int64("size", p.size))
+		attrs = append(attrs, slog.Attr{Key: strconv.Itoa(i), Value: slog.GroupValue(fields...)})
+	}
+	if t.truncated || t.incomplete {
+		attrs = append(attrs, slog.Bool("truncated", true))
+	}
+	return slog.Attr{Key: key, Value: slog.GroupValue(attrs...)}, true
+}
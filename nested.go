@@ -0,0 +1,30 @@
+package slog
+
+import "github.com/gin-gonic/gin"
+
+// NestedPolicy decides which of several middleware instances installed on the
+// same request chain (e.g. at engine and group level) logs the request.
+type NestedPolicy int
+
+const (
+	// NestedLogBoth lets every instance log the request (default).
+	NestedLogBoth NestedPolicy = iota
+	// NestedLogOutermost silences an instance that runs inside another one.
+	NestedLogOutermost
+	// NestedLogInnermost silences an instance when another one runs inside it.
+	NestedLogInnermost
+)
+
+// nestedKey holds the depth of the innermost logging instance reached so far.
+const nestedKey = "_gin-contrib/slog_nested_"
+
+func nestedDepth(c *gin.Context) int {
+	return c.GetInt(nestedKey)
+}
+
+// enterNested records the instance in c and returns its depth, starting at 1.
+func enterNested(c *gin.Context) int {
+	depth := nestedDepth(c) + 1
+	c.Set(nestedKey, depth)
+	return depth
+}
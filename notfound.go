@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"cmp"
+	"context"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+)
+
+// maxNotFoundPaths bounds the number of distinct paths tracked per window.
+const maxNotFoundPaths = 10000
+
+// NotFoundCount is a path and the number of unmatched requests for it.
+type NotFoundCount struct {
+	Path  string `json:"path"`
+	Count int    `json:"count"`
+}
+
+// notFoundSummary aggregates requests for unmatched routes and emits a top-N
+// summary record once per interval instead of one record per request.
+type notFoundSummary struct {
+	interval time.Duration
+	topN     int
+
+	mu     sync.Mutex
+	start  time.Time
+	total  int
+	counts map[string]int
+}
+
+func newNotFoundSummary(interval time.Duration, topN int) *notFoundSummary {
+	if topN <= 0 {
+		topN = 10
+	}
+	return &notFoundSummary{interval: interval, topN: topN, counts: map[string]int{}}
+}
+
+// add counts an unmatched request and, once the interval has elapsed since
+// the window started, emits the summary for that window through l.
+func (s *notFoundSummary) add(l *slog.Logger, level slog.Level, path string, now time.Time) {
+	s.mu.Lock()
+	if s.start.IsZero() {
+		s.start = now
+	}
+	s.total++
+	if _, ok := s.counts[path]; ok || len(s.counts) < maxNotFoundPaths {
+		s.counts[path]++
+	}
+	if now.Sub(s.start) < s.interval {
+		s.mu.Unlock()
+		return
+	}
+	s.emit(l, level, now)
+}
+
+// flush emits the summary of the current window, if it counted any request, e.g. on shutdown.
+func (s *notFoundSummary) flush(l *slog.Logger, level slog.Level, now time.Time) {
+	s.mu.Lock()
+	if s.total == 0 {
+		s.mu.Unlock()
+		return
+	}
+	s.emit(l, level, now)
+}
+
+// emit resets the window and logs its summary. It is called with s.mu held and releases it.
+func (s *notFoundSummary) emit(l *slog.Logger, level slog.Level, now time.Time) {
+	start, total, counts := s.start, s.total, s.counts
+	s.start, s.total, s.counts = time.Time{}, 0, map[string]int{}
+	s.mu.Unlock()
+
+	top := make([]NotFoundCount, 0, len(counts))
+	for p, n := range counts {
+		top = append(top, NotFoundCount{Path: p, Count: n})
+	}
+	slices.SortFunc(top, func(a, b NotFoundCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), cmp.Compare(a.Path, b.Path))
+	})
+	if len(top) > s.topN {
+		top = top[:s.topN]
+	}
+
+	l.LogAttrs(context.Background(), level, "Route not found summary",
+		slog.Time("window_start", start),
+		slog.Time("window_end", now),
+		slog.Int("total", total),
+		slog.Int("unique_paths", len(counts)),
+		slog.Any("top", top),
+	)
+}
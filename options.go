@@ -3,8 +3,12 @@ package slog
 import (
 	"io"
 	"log/slog"
+	"maps"
+	"net/http"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -28,6 +32,16 @@ func WithLogger(fn func(*gin.Context, *slog.Logger) *slog.Logger) Option {
 	})
 }
 
+// WithKeyedLogger derives the request logger with derive, like WithLogger, once per key returned
+// by key (e.g. a client tier or API version) and reuses it for the requests sharing that key,
+// saving a logger and handler allocation per request. Keys should be of low cardinality; past
+// 1024 keys the logger is derived per request.
+func WithKeyedLogger(key func(*gin.Context) string, derive func(key string, l *slog.Logger) *slog.Logger) Option {
+	return optionFunc(func(c *config) {
+		c.keyedLoggers = &keyedLoggers{key: key, derive: derive}
+	})
+}
+
 // WithContext sets a custom context handler for slog.Record.
 func WithContext(fn func(*gin.Context, *slog.Record) *slog.Record) Option {
 	return optionFunc(func(c *config) {
@@ -35,6 +49,48 @@ func WithContext(fn func(*gin.Context, *slog.Record) *slog.Record) Option {
 	})
 }
 
+// WithNotFoundSummary replaces the records of requests matching no route with a summary of the
+// topN most requested paths, emitted with the first such request after each interval.
+func WithNotFoundSummary(interval time.Duration, topN int) Option {
+	return optionFunc(func(c *config) {
+		c.notFoundInterval = interval
+		c.notFoundTopN = topN
+	})
+}
+
+// WithMaxLogsPerSecond writes at most n access log records per second, with bursts of up to n,
+// dropping the excess. A warning summary with the number dropped and its window is emitted with
+// the first request at least 10 seconds after the previous summary. Forced records are not limited.
+func WithMaxLogsPerSecond(n int) Option {
+	return optionFunc(func(c *config) {
+		c.maxLogsPerSecond = n
+	})
+}
+
+// WithRequestHistory keeps the records of the last n logged requests in memory, redacted and
+// encrypted as configured, served by Middleware.HistoryHandler.
+func WithRequestHistory(n int) Option {
+	return optionFunc(func(c *config) {
+		c.historySize = n
+	})
+}
+
+// WithBatching buffers the output of the built-in handler, writing it once maxRecords records are
+// buffered or flushInterval after the first, to cut syscalls under load. Use Middleware.Close on shutdown.
+func WithBatching(maxRecords int, flushInterval time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.batchRecords = maxRecords
+		c.batchInterval = flushInterval
+	})
+}
+
+// WithConflictPolicy sets how attributes added by WithContext that collide with built-in keys are resolved.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return optionFunc(func(c *config) {
+		c.conflictPolicy = policy
+	})
+}
+
 // WithUTC sets UTC mode for logging time.
 func WithUTC(s bool) Option {
 	return optionFunc(func(c *config) {
@@ -49,6 +105,21 @@ func WithSkipPath(s []string) Option {
 	})
 }
 
+// WithSkipRoutes skips logging requests matched to any of the given route templates
+// (c.FullPath()), such as /users/:id.
+func WithSkipRoutes(routes ...string) Option {
+	return optionFunc(func(c *config) {
+		skip := make(map[string]struct{}, len(routes))
+		for _, r := range routes {
+			skip[r] = struct{}{}
+		}
+		c.skippers = append(c.skippers, func(ctx *gin.Context) bool {
+			_, ok := skip[ctx.FullPath()]
+			return ok
+		})
+	})
+}
+
 // WithSkipPathRegexps appends regexp rules for skipPathRegexps in config.
 func WithSkipPathRegexps(regs ...*regexp.Regexp) Option {
 	return optionFunc(func(c *config) {
@@ -59,6 +130,18 @@ func WithSkipPathRegexps(regs ...*regexp.Regexp) Option {
 	})
 }
 
+// WithFilters logs only the requests every filter passes, evaluated once the request has been
+// handled, e.g. slog.Or(slog.StatusFilter(400, 599), slog.LatencyFilter(time.Second)). Repeated
+// calls add filters. Forced records are still logged, and metrics and error rates still count every request.
+func WithFilters(filters ...Filter) Option {
+	return optionFunc(func(c *config) {
+		if c.filters != nil {
+			filters = append([]Filter{c.filters}, filters...)
+		}
+		c.filters = And(filters...)
+	})
+}
+
 // WithSkipper sets a function to skip logging for certain requests.
 func WithSkipper(s Skipper) Option {
 	return optionFunc(func(c *config) {
@@ -66,10 +149,185 @@ func WithSkipper(s Skipper) Option {
 	})
 }
 
+// WithSkipStatusCodes skips logging responses with any of the given status codes, e.g. 404 and 401
+// noise from scanners and unauthenticated probes.
+func WithSkipStatusCodes(codes ...int) Option {
+	return optionFunc(func(c *config) {
+		skip := make(map[int]struct{}, len(codes))
+		for _, code := range codes {
+			skip[code] = struct{}{}
+		}
+		c.skippers = append(c.skippers, func(ctx *gin.Context) bool {
+			_, ok := skip[ctx.Writer.Status()]
+			return ok
+		})
+	})
+}
+
+// WithSkipStatus skips logging responses whose status code fn reports true for.
+func WithSkipStatus(fn func(status int) bool) Option {
+	return optionFunc(func(c *config) {
+		c.skippers = append(c.skippers, func(ctx *gin.Context) bool {
+			return fn(ctx.Writer.Status())
+		})
+	})
+}
+
 // WithWriter sets the log output destination.
 func WithWriter(s io.Writer) Option {
 	return optionFunc(func(c *config) {
 		c.output = s
+		c.outputSet = true
+	})
+}
+
+// WithTestModeOutput sets the output used in gin.TestMode when WithWriter is not set, io.Discard
+// by default so test suites are not flooded with access logs. Pass os.Stderr to see them, or
+// capture records with the slogtest package.
+func WithTestModeOutput(w io.Writer) Option {
+	return optionFunc(func(c *config) {
+		c.testOutput = w
+	})
+}
+
+// WithForceLogHeader logs requests carrying the header regardless of skip and sampling rules.
+// The header value must equal secret when set, and the client IP must be within one of the
+// trusted CIDRs when given; at least one of them is required.
+func WithForceLogHeader(header, secret string, cidrs ...string) Option {
+	return optionFunc(func(c *config) {
+		c.forceLog = &forceLog{header: header, secret: secret, cidrs: cidrs}
+	})
+}
+
+// WithSampleRate keeps only the given fraction (0 to 1) of successful (<400 status) access logs.
+// Client and server errors are always logged.
+func WithSampleRate(rate float64) Option {
+	return optionFunc(func(c *config) {
+		c.sampleRate = rate
+	})
+}
+
+// WithSampler sets a function deciding whether a successful (<400 status) request is logged,
+// returning true to keep it. Client and server errors are always logged.
+func WithSampler(fn func(c *gin.Context) bool) Option {
+	return optionFunc(func(c *config) {
+		c.sampler = fn
+	})
+}
+
+// WithErrorsOnly drops the access logs of successful requests, keeping client and server errors,
+// panics, and requests escalated by WithSlowRequestThreshold, WithLargeResponseThreshold or
+// WithClientAbortDetection. Forced records are still logged, and metrics and error rates still
+// count every request.
+func WithErrorsOnly() Option {
+	return optionFunc(func(c *config) {
+		c.errorsOnly = true
+	})
+}
+
+// WithMinLatency drops the access logs of requests faster than d, whatever their status, for a
+// slow log. Combined with WithErrorsOnly, only the slow failed or escalated requests are logged.
+// Forced records are still logged, and metrics and error rates still count every request.
+func WithMinLatency(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.minLatency = d
+	})
+}
+
+// WithDryRun evaluates skips, sampling, filters, levels and redaction as configured without
+// writing anything, counting into stats what would have been logged at which level, to validate
+// a policy in production, e.g. next to the middleware in use. Hooks, observers, reporters, audit
+// logs, metrics and error rate alerts do not run, no response header is set, and Get(c) and
+// RequestID(c) keep returning the values of any other middleware.
+func WithDryRun(stats *Stats) Option {
+	return optionFunc(func(c *config) {
+		c.dryRun = stats
+	})
+}
+
+// WithPathSampleRates sets sample rates (0 to 1) keyed by route template, e.g. "/users/:id", so
+// parameterized routes are grouped; requests matching no route use their URL path. Overrides WithSampleRate.
+func WithPathSampleRates(rates map[string]float64) Option {
+	return optionFunc(func(c *config) {
+		c.pathSampleRates = rates
+	})
+}
+
+// WithPathSampleRateRegexp appends a sample rate (0 to 1) for URL paths matching the regexp.
+func WithPathSampleRateRegexp(reg *regexp.Regexp, rate float64) Option {
+	return optionFunc(func(c *config) {
+		if reg == nil {
+			return
+		}
+		c.sampleRules = append(c.sampleRules, sampleRule{re: reg, rate: rate})
+	})
+}
+
+// WithHandler sets a custom slog.Handler used for the access log and the logger returned by Get.
+// The handler's own level settings apply, and WithWriter is ignored.
+func WithHandler(h slog.Handler) Option {
+	return optionFunc(func(c *config) {
+		c.handler = h
+	})
+}
+
+// WithRotatingFile writes logs to the file at path, rotating it once it reaches maxSizeMB and
+// keeping at most maxBackups rotated files no older than maxAgeDays (0 disables a limit).
+// Rotated files are gzipped when compress is set. Overrides WithWriter.
+func WithRotatingFile(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) Option {
+	return optionFunc(func(c *config) {
+		c.rotation = &rotation{
+			path:       path,
+			maxSize:    int64(maxSizeMB) << 20,
+			maxBackups: maxBackups,
+			maxAge:     time.Duration(maxAgeDays) * 24 * time.Hour,
+			compress:   compress,
+		}
+	})
+}
+
+// WithTee writes text records to several writers, each with its own minimum level, e.g. everything
+// to stdout and warn+ to an alerting pipe. Replaces WithHandler and WithWriter.
+func WithTee(writers ...WeightedWriter) Option {
+	return optionFunc(func(c *config) {
+		sinks := make([]Sink, len(writers))
+		for i, w := range writers {
+			sinks[i] = w.sink()
+		}
+		c.handler = &multiHandler{sinks: sinks}
+	})
+}
+
+// WithSyslog writes records as RFC 5424 messages to the syslog daemon at addr (the local daemon
+// when network and addr are empty) with the given tag as app name, mapping levels to severities
+// (error to ERR, warn to WARNING, ...) and attributes to structured data. Overrides WithHandler.
+func WithSyslog(network, addr, tag string) Option {
+	return optionFunc(func(c *config) {
+		c.syslog = &syslogTarget{network: network, addr: addr, tag: tag}
+	})
+}
+
+// WithSinks writes the access log and the logger returned by Get to several handlers, each
+// gated by its own minimum level (e.g. console Debug, file Info, alerting Warn). Replaces WithHandler.
+func WithSinks(sinks ...Sink) Option {
+	return optionFunc(func(c *config) {
+		c.handler = &multiHandler{sinks: sinks}
+	})
+}
+
+// WithSlogLogger sets an existing *slog.Logger as the base logger, so its handler, attrs and
+// groups are inherited by the access log and the logger returned by Get. Overrides WithHandler.
+func WithSlogLogger(l *slog.Logger) Option {
+	return optionFunc(func(c *config) {
+		c.baseLogger = l
+	})
+}
+
+// WithRouteLoggers derives the per-request logger from a pool of per-route loggers carrying
+// method and route attributes, instead of deriving them on every request.
+func WithRouteLoggers(p *RouteLoggers) Option {
+	return optionFunc(func(c *config) {
+		c.routeLoggers = p
 	})
 }
 
@@ -80,6 +338,31 @@ func WithDefaultLevel(lvl slog.Level) Option {
 	})
 }
 
+// WithLevelVar backs the default level (<400 status) and the built-in handler's level with v,
+// so it can be changed at runtime with v.Set. Takes precedence over WithDefaultLevel.
+func WithLevelVar(v *slog.LevelVar) Option {
+	return optionFunc(func(c *config) {
+		c.levelVar = v
+	})
+}
+
+// WithLevels backs the default, client error and server error levels with l, so they can be
+// changed at runtime, e.g. through LevelHandler. Takes precedence over the individual level options.
+func WithLevels(l *Levels) Option {
+	return optionFunc(func(c *config) {
+		c.levels = l
+	})
+}
+
+// WithStartupVerbosity uses level as the default level (<400 status) for the given duration after
+// the middleware is created, e.g. slog.LevelDebug during the first minutes of a rollout.
+func WithStartupVerbosity(level slog.Level, d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.startupLevel = level
+		c.startupDuration = d
+	})
+}
+
 // WithClientErrorLevel sets client error log level (400-499).
 func WithClientErrorLevel(lvl slog.Level) Option {
 	return optionFunc(func(c *config) {
@@ -94,6 +377,14 @@ func WithServerErrorLevel(lvl slog.Level) Option {
 	})
 }
 
+// WithRedirectLevel sets the log level of 3xx responses, such as 301, 302 and 304, which otherwise
+// share the default level. A level below the handler's, e.g. Debug, suppresses them.
+func WithRedirectLevel(level slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.redirectLevel = level
+	})
+}
+
 // WithPathLevel sets path-specific logging levels.
 func WithPathLevel(m map[string]slog.Level) Option {
 	return optionFunc(func(c *config) {
@@ -101,6 +392,188 @@ func WithPathLevel(m map[string]slog.Level) Option {
 	})
 }
 
+// WithRouteLevel sets route-specific logging levels, keyed by route template (c.FullPath()) such
+// as /users/:id. Levels set by WithPathLevel take precedence.
+func WithRouteLevel(m map[string]slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.routeLevels = m
+	})
+}
+
+// WithMethodLevel sets HTTP-method-specific logging levels, e.g. GET at debug. Path and route
+// levels take precedence.
+func WithMethodLevel(m map[string]slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.methodLevels = make(map[string]slog.Level, len(m))
+		for method, lvl := range m {
+			c.methodLevels[strings.ToUpper(method)] = lvl
+		}
+	})
+}
+
+// WithLevelConfigFile loads path, status and route template levels from a YAML or JSON file
+// such as a mounted ConfigMap, checked for changes every 5 seconds, e.g.
+// {"paths": {"/foo": "debug"}, "routes": {"/users/:id": "warn"}, "status": {"404": "debug"}}.
+// Its levels take precedence over the corresponding options; a file that fails to reload keeps
+// the previous levels and logs a warning.
+func WithLevelConfigFile(path string) Option {
+	return optionFunc(func(c *config) {
+		c.levelFile = &levelFile{path: path}
+	})
+}
+
+// WithRouteOptions applies opts on top of the other options to the routes whose template
+// (c.FullPath()) is prefix or lies under it, e.g. "/internal"; the longest prefix wins. Options
+// configuring the handler, output, metrics and sampling state have no effect in overrides.
+func WithRouteOptions(prefix string, opts ...Option) Option {
+	return optionFunc(func(c *config) {
+		c.routeOverrides = append(c.routeOverrides, routeOverride{prefix: prefix, opts: opts})
+	})
+}
+
+// WithLevelByCIDR sets client-network-specific logging levels (<400 status), keyed by CIDR or IP.
+func WithLevelByCIDR(m map[string]slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.levelByCIDR = m
+	})
+}
+
+// WithClientIPResolver sets the function deriving the ip attribute, overriding WithTrustedProxies,
+// e.g. ForwardedClientIP for RFC 7239 Forwarded headers.
+func WithClientIPResolver(fn func(*gin.Context) string) Option {
+	return optionFunc(func(c *config) {
+		c.ipResolver = fn
+	})
+}
+
+// WithAnonymizeIP logs client addresses with the last octet of IPv4 and the last 80 bits of IPv6
+// addresses zeroed, keeping them usable for rough geo and abuse analysis.
+func WithAnonymizeIP(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.anonymizeIP = enabled
+	})
+}
+
+// WithHashedIP logs client addresses as a hash salted with salt, applied after WithAnonymizeIP.
+func WithHashedIP(salt string) Option {
+	return optionFunc(func(c *config) {
+		c.ipHashSalt = []byte(salt)
+	})
+}
+
+// WithGeoIP logs the attributes fn returns for the client IP (e.g. country and ASN from a MaxMind
+// database) as the geo group. fn is called once per logged request and IP, before anonymization.
+func WithGeoIP(fn GeoIPFunc) Option {
+	return optionFunc(func(c *config) {
+		c.geoIP = fn
+	})
+}
+
+// WithPrincipal logs the subject fn returns as the principal field along with its extra
+// attributes, e.g. BasicAuthPrincipal or JWTPrincipal. fn runs after the handlers.
+func WithPrincipal(fn PrincipalFunc) Option {
+	return optionFunc(func(c *config) {
+		c.principal = fn
+	})
+}
+
+// WithTenant adds the tenant fn returns, e.g. TenantFromHeader("X-Tenant-ID") or
+// TenantFromSubdomain("example.com"), as the tenant field of the access log and of Get(c).
+func WithTenant(fn func(*gin.Context) string) Option {
+	return optionFunc(func(c *config) {
+		c.tenant = fn
+	})
+}
+
+// WithBaggage adds the OpenTelemetry baggage members named by keys, or all members when none
+// are given, as the baggage group of the access log and of Get(c).
+func WithBaggage(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.baggage = true
+		c.baggageKeys = keys
+	})
+}
+
+// WithConnectionInfo logs the request protocol (HTTP/1.1, HTTP/2.0, HTTP/3.0), Host header and
+// scheme (https for TLS connections, X-Forwarded-Proto behind proxies) as proto, host and scheme.
+func WithConnectionInfo() Option {
+	return optionFunc(func(c *config) {
+		c.connInfo = true
+	})
+}
+
+// WithRequestSize logs the declared Content-Length as request_size and, when WithRequestBody
+// captures the body, the bytes the handlers actually read as request_body_read.
+func WithRequestSize() Option {
+	return optionFunc(func(c *config) {
+		c.requestSize = true
+	})
+}
+
+// WithContentTypes logs the request and response Content-Type headers as request_content_type
+// and response_content_type.
+func WithContentTypes() Option {
+	return optionFunc(func(c *config) {
+		c.contentTypes = true
+	})
+}
+
+// WithMultipartMetadata logs the field name, filename, content type and size of each part of
+// multipart/form-data requests as the multipart group, inspecting at most maxBytes of the body as
+// the handlers read it. Part contents are never logged.
+func WithMultipartMetadata(maxBytes int64) Option {
+	return optionFunc(func(c *config) {
+		c.multipartMax = maxBytes
+	})
+}
+
+// WithStreamingMetrics logs the time to the first response byte as ttfb and the time from it to
+// the end of the request as stream_duration, telling slow starts from long streams (e.g. SSE).
+func WithStreamingMetrics(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.streamingMetrics = enabled
+	})
+}
+
+// WithForwardedChain logs the proxy chain of the request (Forwarded for= nodes or X-Forwarded-For
+// entries, client first) as the forwarded_for array. With WithTrustedProxies, only the entries
+// appended by trusted proxies are logged.
+func WithForwardedChain() Option {
+	return optionFunc(func(c *config) {
+		c.forwardedChain = true
+	})
+}
+
+// WithForwarded logs the for, by, host and proto parameters of the client-side element of the
+// RFC 7239 Forwarded header (or of the X-Forwarded-For, -Host and -Proto headers) as the forwarded
+// group and, with chain, every element, client first, as the proxy_chain array (see ProxyHops).
+// With WithTrustedProxies, only the elements appended by trusted proxies are logged.
+func WithForwarded(chain bool) Option {
+	return optionFunc(func(c *config) {
+		c.forwarded = true
+		c.proxyChain = chain
+	})
+}
+
+// WithTrustedProxies sets the proxies (CIDRs or IPs) whose X-Forwarded-For entries are trusted
+// when deriving the ip attribute, independent of gin's engine settings.
+func WithTrustedProxies(cidrs []string) Option {
+	return optionFunc(func(c *config) {
+		c.trustedProxies = cidrs
+		if c.trustedProxies == nil {
+			c.trustedProxies = []string{}
+		}
+	})
+}
+
+// WithLevelMapper sets a function mapping the computed level right before the record is handled,
+// e.g. to collapse Warn into Info during a noisy period.
+func WithLevelMapper(fn func(slog.Level) slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.levelMapper = fn
+	})
+}
+
 // WithMessage sets a custom log message for requests.
 func WithMessage(message string) Option {
 	return optionFunc(func(c *config) {
@@ -108,6 +581,445 @@ func WithMessage(message string) Option {
 	})
 }
 
+// WithMessages sets per-status-class log messages, falling back to WithMessage for missing classes.
+func WithMessages(messages map[StatusClass]string) Option {
+	return optionFunc(func(c *config) {
+		c.messages = messages
+	})
+}
+
+// WithRouteMeta sets the log message (kept when empty) and static attributes of the requests
+// matching route, a route template as returned by c.FullPath(), e.g. "/orders/:id", so logs can
+// be keyed by operation name: WithRouteMeta("/orders", "Create order", slog.String("operation", "CreateOrder")).
+func WithRouteMeta(route, message string, attrs ...slog.Attr) Option {
+	return optionFunc(func(c *config) {
+		meta := maps.Clone(c.routeMeta)
+		if meta == nil {
+			meta = map[string]routeMeta{}
+		}
+		meta[route] = routeMeta{message: message, attrs: attrs}
+		c.routeMeta = meta
+	})
+}
+
+// WithErrorsInMessage appends gin errors to the message ("Request with errors: ...") instead of
+// logging them as the errors group, as earlier versions did.
+func WithErrorsInMessage(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.errorsInMessage = enabled
+	})
+}
+
+// WithErrorStackTrace logs, on 5xx responses, the stack frames of errors attached with
+// ErrorWithStack or wrapping an error with a StackTrace method (e.g. github.com/pkg/errors) in the
+// errors group.
+func WithErrorStackTrace(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.errorStackTrace = enabled
+	})
+}
+
+// WithErrorTypes sets which gin error types are logged, e.g. gin.ErrorTypePrivate (default: gin.ErrorTypeAny).
+func WithErrorTypes(t gin.ErrorType) Option {
+	return optionFunc(func(c *config) {
+		c.errorTypes = t
+	})
+}
+
+// WithErrorTypeAttrs enables logging gin errors grouped by type (private_errors, public_errors, ...),
+// each error including its JSON metadata, or its LogValue when the metadata is a slog.LogValuer,
+// and the number of errors per type as the error_counts group.
+func WithErrorTypeAttrs(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.errorTypeAttrs = enabled
+	})
+}
+
+// WithFieldNames renames built-in attribute keys, e.g. {FieldStatus: "http.status_code"}.
+func WithFieldNames(names map[Field]string) Option {
+	return optionFunc(func(c *config) {
+		c.fieldNames = names
+	})
+}
+
+// WithCostSummary adds a cost group (bytes_in, bytes_out, duration, cpu_hint) for chargeback
+// reporting. cpu_hint is a coarse process-wide CPU time delta, only meaningful in aggregate.
+func WithCostSummary(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.costSummary = enabled
+	})
+}
+
+// WithECS names built-in attributes after Elastic Common Schema fields (http.request.method,
+// http.response.status_code, url.path, client.ip, event.duration, user_agent.original, ...).
+// With a JSON handler, event.duration is logged in nanoseconds as ECS expects.
+func WithECS() Option {
+	return WithFieldNames(ecsFieldNames)
+}
+
+// WithCombinedLogFormat writes each request as an Apache/NGINX combined log format line to the
+// writer set by WithWriter instead of logging a slog record. Query parameters set by
+// WithRedactedQueryParams are redacted.
+func WithCombinedLogFormat() Option {
+	return optionFunc(func(c *config) {
+		c.combinedLog = true
+	})
+}
+
+// WithOTelSemConv names built-in attributes per OpenTelemetry HTTP semantic conventions
+// (http.request.method, url.path, http.response.status_code, ...) and adds server.address,
+// server.port and network.protocol.version.
+func WithOTelSemConv() Option {
+	return optionFunc(func(c *config) {
+		c.fieldNames = otelFieldNames
+		c.otelSemConv = true
+	})
+}
+
+// WithGCPFormat renders the request as a Google Cloud Logging httpRequest group with
+// logging.googleapis.com/trace fields (qualified with $GOOGLE_CLOUD_PROJECT when set), and makes
+// the built-in handler write JSON with severity and message fields (see GCPReplaceAttr).
+func WithGCPFormat() Option {
+	return optionFunc(func(c *config) {
+		c.layout = gcpLayout(gcpProject())
+		c.gcp = true
+	})
+}
+
+// WithPrettyConsole makes the built-in handler write for humans: colored levels and statuses,
+// aligned status, method, latency and path columns, compact durations and group attributes on
+// indented lines. It is the default in gin debug mode when the output is a terminal.
+func WithPrettyConsole() Option {
+	return optionFunc(func(c *config) {
+		c.pretty = true
+	})
+}
+
+// WithLegacyLoggerFields logs the status, method, path, ip, latency, user_agent and body_size
+// fields of gin-contrib/logger, and makes the built-in handler write its JSON message and level.
+func WithLegacyLoggerFields() Option {
+	return optionFunc(func(c *config) {
+		c.layout = legacyLayout
+		c.legacyFields = true
+	})
+}
+
+// WithSlogGinFields logs the request and response groups of samber/slog-gin and the request
+// ID, when enabled, under its id key.
+func WithSlogGinFields() Option {
+	return optionFunc(func(c *config) {
+		c.layout = slogGinLayout
+		c.fieldNames = slogGinFieldNames
+	})
+}
+
+// WithContextKeys logs the values set on the gin context under the given keys, e.g. by an
+// authentication middleware, as attributes of the same names. Missing keys are skipped.
+func WithContextKeys(keys ...string) Option {
+	return optionFunc(func(c *config) {
+		c.contextKeys = keys
+	})
+}
+
+// WithRedaction redacts the values selected by rules, e.g. RedactKey("password") or
+// RedactBearerTokens, from every attribute and message logged through the middleware's logger,
+// including headers, query strings, captured bodies and attributes added by handlers.
+func WithRedaction(rules ...RedactRule) Option {
+	return optionFunc(func(c *config) {
+		c.redactor = newRedactor(rules)
+	})
+}
+
+// WithEncryptedFields logs the values of the given top-level attributes, built-in or custom,
+// AES-GCM encrypted with keys from keys, so authorized parties can recover them with DecryptValue.
+func WithEncryptedFields(fields []string, keys KeyProvider) Option {
+	return optionFunc(func(c *config) {
+		c.encryptedFields = make(map[string]struct{}, len(fields))
+		for _, f := range fields {
+			c.encryptedFields[f] = struct{}{}
+		}
+		c.keyProvider = keys
+	})
+}
+
+// WithFields logs only the given built-in fields among status, method, path, query, route, ip,
+// latency, referer, user_agent and body_size. Optional fields, and the method and route carried by
+// WithRouteLoggers loggers, are not affected.
+func WithFields(fields ...Field) Option {
+	return optionFunc(func(c *config) {
+		c.fields = make(map[Field]struct{}, len(fields))
+		for _, f := range fields {
+			c.fields[f] = struct{}{}
+		}
+	})
+}
+
+// WithHealthEndpoints skips successful requests to the given health check paths (e.g. /healthz,
+// /readyz, /livez) and logs their failures (status >= 400) at warn level. Repeated calls add paths.
+func WithHealthEndpoints(paths ...string) Option {
+	return optionFunc(func(c *config) {
+		skip := make(map[string]struct{}, len(paths))
+		for _, p := range paths {
+			skip[p] = struct{}{}
+		}
+		// Copy on write: the map may be shared with route override configurations.
+		health := maps.Clone(skip)
+		maps.Copy(health, c.healthPaths)
+		c.healthPaths = health
+		c.skippers = append(c.skippers, func(ctx *gin.Context) bool {
+			_, ok := skip[ctx.Request.URL.Path]
+			return ok && ctx.Writer.Status() < http.StatusBadRequest
+		})
+	})
+}
+
+// WithSkipHealthChecks skips successful requests from common health check probes: the paths
+// /healthz, /livez, /readyz and /ping, and User-Agents such as kube-probe/* and ELB-HealthChecker.
+// Failed probes are still logged.
+func WithSkipHealthChecks() Option {
+	return optionFunc(func(c *config) {
+		c.skippers = append(c.skippers, probeSkipper)
+	})
+}
+
+// WithRouteNormalizer sets the route attribute, route logger and per-route sample rate key of a
+// request, e.g. to collapse catch-all routes like /files/*filepath to bounded cardinality.
+func WithRouteNormalizer(fn func(c *gin.Context) string) Option {
+	return optionFunc(func(c *config) {
+		c.routeNormalizer = fn
+	})
+}
+
+// WithNestedPolicy decides whether this instance logs when nested with other SetLogger instances
+// on the same request, e.g. at engine and group level (default: NestedLogBoth).
+func WithNestedPolicy(p NestedPolicy) Option {
+	return optionFunc(func(c *config) {
+		c.nestedPolicy = p
+	})
+}
+
+// WithDetachedContext delivers access records with a context that is not canceled when the
+// client disconnects, keeping the request context's values. A positive timeout bounds delivery.
+func WithDetachedContext(timeout time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.detachContext = true
+		c.detachTimeout = timeout
+	})
+}
+
+// WithErrorHook calls fn after logging any request whose level is at or above the server error
+// level, e.g. to bump a counter or page someone without parsing log output.
+func WithErrorHook(fn ErrorHook) Option {
+	return optionFunc(func(c *config) {
+		c.errorHooks = append(c.errorHooks, fn)
+	})
+}
+
+// WithReporter calls fn with the level, message and attributes of every request logged at or
+// above level, e.g. to forward 5xx responses and panics to Sentry with the attributes of the log
+// line. Attributes of the request logger, such as request_id, are not included.
+func WithReporter(level slog.Level, fn Reporter) Option {
+	return optionFunc(func(c *config) {
+		c.reporters = append(c.reporters, reporter{level: level, fn: fn})
+	})
+}
+
+// WithOnLog calls fn with every emitted access log record, e.g. to feed in-process dashboards or
+// ring buffers, and with the records of requests dropped by sampling, flagged "sampled_out": true.
+func WithOnLog(fn LogObserver) Option {
+	return optionFunc(func(c *config) {
+		c.observers = append(c.observers, fn)
+	})
+}
+
+// WithErrorRateAlert calls alert with the ratio of 5xx responses among the requests logged during
+// the last window and their number when the ratio rises above threshold (e.g. 0.05), once per
+// crossing. It needs at least 10 requests in the window and runs on the request goroutine.
+func WithErrorRateAlert(window time.Duration, threshold float64, alert func(rate float64, requests int)) Option {
+	return optionFunc(func(c *config) {
+		c.errorRate = newErrorRate(window, threshold, alert)
+	})
+}
+
+// WithAccessRecordHook calls fn with the typed record of every logged request, after it is handled.
+func WithAccessRecordHook(fn AccessHook) Option {
+	return optionFunc(func(c *config) {
+		c.accessHooks = append(c.accessHooks, fn)
+	})
+}
+
+// WithSlowRequestThreshold logs requests slower than d at level or above, whatever their status,
+// and marks them with "slow": true.
+func WithSlowRequestThreshold(d time.Duration, level slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.slowThreshold = d
+		c.slowLevel = level
+	})
+}
+
+// WithLatencyBreakdown adds a latency_breakdown group splitting the latency into the time spent
+// in the middleware registered before MarkHandlers (when used), in the handlers, and writing the
+// response body from the first to the last write.
+func WithLatencyBreakdown() Option {
+	return optionFunc(func(c *config) {
+		c.latencyBreakdown = true
+	})
+}
+
+// WithLargeResponseThreshold logs responses with a body larger than bytes at level or above,
+// whatever their status, and marks them with "large_response": true.
+func WithLargeResponseThreshold(bytes int64, level slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.largeResponse = bytes
+		c.largeResponseLevel = level
+	})
+}
+
+// WithErrorID assigns a short random id to 5xx responses, set as the headerName response header
+// and logged as error_id, so a customer report can be matched to its log record. Handlers can
+// include it in the error body with ErrorID(c).
+func WithErrorID(headerName string) Option {
+	return optionFunc(func(c *config) {
+		c.errorIDHeader = headerName
+	})
+}
+
+// WithDeadlineInfo logs, when the request context has a deadline, the time from the start of the
+// request to it as deadline_budget and the time left at the end as deadline_remaining (negative once
+// passed), and marks requests whose context deadline was exceeded with "timed_out": true.
+func WithDeadlineInfo() Option {
+	return optionFunc(func(c *config) {
+		c.deadlineInfo = true
+	})
+}
+
+// WithClientAbortDetection marks requests whose client disconnected early (canceled request
+// context or broken pipe) with "client_aborted": true and logs them at level, whatever their status.
+// A request context error is logged as context_error.
+func WithClientAbortDetection(level slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.abortDetection = true
+		c.abortLevel = level
+	})
+}
+
+// WithRequestStartLog additionally logs a "Request started" record with the method, path, client IP
+// and request id at level before the handlers run, so hanging requests show up in the logs.
+func WithRequestStartLog(level slog.Level) Option {
+	return optionFunc(func(c *config) {
+		c.startLog = true
+		c.startLevel = level
+	})
+}
+
+// WithAuditLogger additionally writes requests selected by selector (e.g. mutating methods on
+// /admin/*) to handler as "Audit" records with the method, route, status, outcome, principal and
+// request id. Audit records are never skipped or sampled.
+func WithAuditLogger(handler slog.Handler, selector func(*gin.Context) bool) Option {
+	return optionFunc(func(c *config) {
+		c.audit = &auditLog{handler: handler, selector: selector}
+	})
+}
+
+// WithStaticAttrs adds attributes such as the service name, environment, version or region to
+// the base logger, so they appear on the access log and on everything logged through Get(c).
+func WithStaticAttrs(attrs ...slog.Attr) Option {
+	return optionFunc(func(c *config) {
+		c.staticAttrs = append(c.staticAttrs, attrs...)
+	})
+}
+
+// WithHostMetadata adds the hostname, pid and pod (POD_NAME) and container (HOSTNAME) names,
+// resolved once at setup, to the base logger.
+func WithHostMetadata(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.hostMetadata = enabled
+	})
+}
+
+// WithLatencyFormat sets how the latency attribute is logged: as a duration (default), as
+// fractional milliseconds or as integer nanoseconds.
+func WithLatencyFormat(f LatencyFormat) Option {
+	return optionFunc(func(c *config) {
+		c.latencyFormat = f
+	})
+}
+
+// WithTimeFormat sets the time layout (e.g. time.RFC3339Nano) of records written by the built-in handler.
+func WithTimeFormat(layout string) Option {
+	return optionFunc(func(c *config) {
+		c.timeFormat = layout
+	})
+}
+
+// WithTimeKey sets the key of the record time written by the built-in handler, e.g. "@timestamp".
+func WithTimeKey(key string) Option {
+	return optionFunc(func(c *config) {
+		c.timeKey = key
+	})
+}
+
+// WithHandlerOptions sets the options of the built-in handler, e.g. AddSource or a ReplaceAttr
+// function for global key rewriting, applied after the middleware's own. Without a Level, the
+// default level applies.
+func WithHandlerOptions(opts *slog.HandlerOptions) Option {
+	return optionFunc(func(c *config) {
+		c.handlerOptions = opts
+	})
+}
+
+// WithHandlerMiddleware decorates the base handler with mws, the first one outermost, so handler
+// decorators from the slog ecosystem compose without pre-building the logger. Encryption set by
+// WithEncryptedFields applies before them.
+func WithHandlerMiddleware(mws ...HandlerMiddleware) Option {
+	return optionFunc(func(c *config) {
+		c.handlerMiddleware = append(c.handlerMiddleware, mws...)
+	})
+}
+
+// WithGroup nests the attributes of the access log records under the group name, e.g. "http",
+// keeping them apart from application attributes. FieldValue does not look into the group.
+func WithGroup(name string) Option {
+	return optionFunc(func(c *config) {
+		c.group = name
+	})
+}
+
+// WithGraphQL logs the operation name and type (query, mutation, subscription) of requests to the
+// GraphQL endpoint at path as graphql_operation and graphql_type, reading at most 64 KiB of the body.
+func WithGraphQL(path string) Option {
+	return optionFunc(func(c *config) {
+		c.graphqlPath = path
+	})
+}
+
+// WithCurlCommand logs a curl command reproducing responses with a status of at least minStatus
+// (500 when 0) as curl: the method, URL with redacted query values, the given headers with hidden
+// headers redacted, and the request body when WithRequestBody captures it.
+func WithCurlCommand(minStatus int, headers ...string) Option {
+	return optionFunc(func(c *config) {
+		if minStatus == 0 {
+			minStatus = http.StatusInternalServerError
+		}
+		c.curl = &curlRepro{minStatus: minStatus, headers: headers}
+	})
+}
+
+// WithMetrics passes every request, including skipped and sampled out ones, to m, e.g. the
+// Prometheus recorder of the github.com/gin-contrib/slog/metrics package.
+func WithMetrics(m MetricsRecorder) Option {
+	return optionFunc(func(c *config) {
+		c.metrics = m
+	})
+}
+
+// WithLatencyBuckets adds a latency_bucket label attribute using the given bucket bounds.
+func WithLatencyBuckets(bounds []time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.latencyBuckets = slices.Sorted(slices.Values(bounds))
+	})
+}
+
 // WithSpecificLogLevelByStatusCode sets specific log level per HTTP status.
 func WithSpecificLogLevelByStatusCode(statusCodes map[int]slog.Level) Option {
 	return optionFunc(func(c *config) {
@@ -122,6 +1034,125 @@ func WithRequestHeader(enabled bool) Option {
 	})
 }
 
+// WithHeaderAttrs logs headers as top-level attributes, mapping header names to attribute keys,
+// e.g. {"X-API-Version": "api_version", "X-Cache": "cache_status"}, independently of
+// WithRequestHeader. The request header is used, or else the response header; hidden headers are
+// dropped or masked like in the headers group.
+func WithHeaderAttrs(headers map[string]string) Option {
+	return optionFunc(func(c *config) {
+		c.headerAttrs = make([]headerAttr, 0, len(headers))
+		for _, h := range slices.Sorted(maps.Keys(headers)) {
+			c.headerAttrs = append(c.headerAttrs, headerAttr{header: h, key: headers[h]})
+		}
+	})
+}
+
+// WithURI enables logging the reconstructed request target (path and query) as the uri attribute.
+// Values of the query parameters set by WithRedactedQueryParams are redacted.
+func WithURI(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.withURI = enabled
+	})
+}
+
+// WithRedactedQueryParams sets query parameter names whose values are redacted in uri. Only works with WithURI enabled.
+func WithRedactedQueryParams(params []string) Option {
+	return optionFunc(func(c *config) {
+		c.redactedQueryParams = make(map[string]struct{}, len(params))
+		for _, p := range params {
+			c.redactedQueryParams[strings.ToLower(p)] = struct{}{}
+		}
+	})
+}
+
+// WithTraceID adds trace_id and span_id from the OpenTelemetry span in the request context
+// to the access log and the logger returned by Get.
+func WithTraceID() Option {
+	return optionFunc(func(c *config) {
+		c.withTraceID = true
+	})
+}
+
+// WithRequestID reads the request id from the given header (default: X-Request-ID), generating a
+// UUID when it is absent or invalid. The id is echoed on the response, available via RequestID(c),
+// and added as request_id to the access log and the logger returned by Get.
+func WithRequestID(header string) Option {
+	return optionFunc(func(c *config) {
+		c.requestIDHeader = header
+		if header == "" {
+			c.requestIDHeader = requestIDHeader
+		}
+	})
+}
+
+// WithRequestIDGenerator sets the function generating missing request ids. Only works with WithRequestID.
+func WithRequestIDGenerator(fn func() string) Option {
+	return optionFunc(func(c *config) {
+		if fn == nil {
+			return
+		}
+		c.requestIDGenerator = fn
+	})
+}
+
+// WithDebugRequests fully captures the next occurrence of each request id registered in d.
+func WithDebugRequests(d *DebugRequests) Option {
+	return optionFunc(func(c *config) {
+		c.debugRequests = d
+	})
+}
+
+// WithLevelHeader fully captures (debug level, visible headers, request and response bodies, no
+// skipping or sampling) requests carrying the given header for which allowed returns true, e.g.
+// requests from internal networks or carrying a signed token. As with WithDebugRequests, the
+// records of these requests bypass the level of the handler. allowed must not be nil.
+func WithLevelHeader(header string, allowed func(*gin.Context) bool) Option {
+	return optionFunc(func(c *config) {
+		c.levelHeader = &levelHeader{name: header, allowed: allowed}
+	})
+}
+
+// WithTraceHeaders adds trace_id and span_id parsed from the first recognized trace header format
+// (default: W3C traceparent) without the OpenTelemetry SDK, and echoes the header on the response.
+// An OpenTelemetry span found by WithTraceID takes precedence.
+func WithTraceHeaders(formats ...TraceFormat) Option {
+	return optionFunc(func(c *config) {
+		c.traceFormats = formats
+		if len(formats) == 0 {
+			c.traceFormats = []TraceFormat{TraceFormatW3C}
+		}
+	})
+}
+
+// WithRequestBody enables logging up to maxBytes of the request body read by the handlers, for
+// the given content types (default: application/json). Entries such as "text/*" match a whole type.
+func WithRequestBody(maxBytes int, contentTypes ...string) Option {
+	return optionFunc(func(c *config) {
+		c.requestBodyMax = maxBytes
+		c.requestBodyTypes = contentTypes
+		if len(contentTypes) == 0 {
+			c.requestBodyTypes = []string{"application/json"}
+		}
+	})
+}
+
+// WithResponseBodyOnError enables logging up to maxBytes of the response body for 4xx/5xx responses.
+func WithResponseBodyOnError(maxBytes int) Option {
+	return optionFunc(func(c *config) {
+		c.responseBodyMax = maxBytes
+	})
+}
+
+// WithBodyDigest logs the digest and byte count of the request body read by the handlers, e.g.
+// request_body_sha256 and request_body_bytes, and of the response body too when response is set,
+// as proof of what was exchanged without logging payloads. Composes with WithRequestBody.
+func WithBodyDigest(algorithm DigestAlgorithm, response bool) Option {
+	return optionFunc(func(c *config) {
+		c.bodyDigest = algorithm
+		c.responseDigest = response
+	})
+}
+
 // WithHiddenRequestHeaders sets request header names to be hidden. Only works with WithRequestHeader enabled.
 func WithHiddenRequestHeaders(headers []string) Option {
 	return optionFunc(func(c *config) {
@@ -131,3 +1162,68 @@ func WithHiddenRequestHeaders(headers []string) Option {
 		}
 	})
 }
+
+// WithAdditionalHiddenRequestHeaders hides the given request headers in addition to the default
+// (or previously set) hidden headers. Only works with WithRequestHeader enabled.
+func WithAdditionalHiddenRequestHeaders(headers ...string) Option {
+	return optionFunc(func(c *config) {
+		hidden := maps.Clone(c.hiddenRequestHeaders)
+		if hidden == nil {
+			hidden = make(map[string]struct{}, len(headers))
+		}
+		for _, h := range headers {
+			hidden[strings.ToLower(h)] = struct{}{}
+		}
+		c.hiddenRequestHeaders = hidden
+	})
+}
+
+// WithNoDefaultHiddenHeaders logs every request header, including Authorization and Cookie,
+// unless hidden by a later WithAdditionalHiddenRequestHeaders. Only works with WithRequestHeader enabled.
+func WithNoDefaultHiddenHeaders() Option {
+	return optionFunc(func(c *config) {
+		c.hiddenRequestHeaders = map[string]struct{}{}
+	})
+}
+
+// WithHeaderMasking logs hidden request headers masked instead of dropping them, keeping the auth
+// scheme and the first prefixLen characters of the credential, e.g. "Bearer abc1****".
+func WithHeaderMasking(prefixLen int) Option {
+	return optionFunc(func(c *config) {
+		c.headerMaskPrefix = max(prefixLen, 0)
+	})
+}
+
+// WithErrorWriter attaches the output written to w.For(c) while serving a request, such as the
+// panics recovered by w.Recovery, to that request's log record.
+func WithErrorWriter(w *ErrorWriter) Option {
+	return optionFunc(func(c *config) {
+		c.errorWriter = w
+	})
+}
+
+// WithSkipBareRequests disables the minimal record logged for requests without an *http.Request
+// or URL (as built by some tests and internal dispatchers); such requests are never fully logged.
+func WithSkipBareRequests(skip bool) Option {
+	return optionFunc(func(c *config) {
+		c.skipBareRequests = skip
+	})
+}
+
+// WithRecovery enables recovering from panics raised by downstream handlers.
+// The request is aborted with a 500 status and the panic is added to the log record.
+func WithRecovery(enabled bool) Option {
+	return optionFunc(func(c *config) {
+		c.recovery = enabled
+	})
+}
+
+// WithPanicFormatter sets how recovered panic values are rendered. Only works with WithRecovery enabled.
+func WithPanicFormatter(fn func(v any) slog.Value) Option {
+	return optionFunc(func(c *config) {
+		if fn == nil {
+			return
+		}
+		c.panicFormatter = fn
+	})
+}
@@ -0,0 +1,108 @@
+package otlp
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// The types below mirror the OTLP/HTTP JSON encoding of logs.
+
+type exportRequest struct {
+	ResourceLogs []resourceLogs `json:"resourceLogs"`
+}
+
+type resourceLogs struct {
+	Resource  resource    `json:"resource"`
+	ScopeLogs []scopeLogs `json:"scopeLogs"`
+}
+
+type resource struct {
+	Attributes []keyValue `json:"attributes,omitempty"`
+}
+
+type scopeLogs struct {
+	Scope      scope       `json:"scope"`
+	LogRecords []logRecord `json:"logRecords"`
+}
+
+type scope struct {
+	Name string `json:"name"`
+}
+
+type logRecord struct {
+	TimeUnixNano         string     `json:"timeUnixNano"`
+	ObservedTimeUnixNano string     `json:"observedTimeUnixNano"`
+	SeverityNumber       int        `json:"severityNumber"`
+	SeverityText         string     `json:"severityText"`
+	Body                 anyValue   `json:"body"`
+	Attributes           []keyValue `json:"attributes,omitempty"`
+	TraceID              string     `json:"traceId,omitempty"`
+	SpanID               string     `json:"spanId,omitempty"`
+}
+
+type keyValue struct {
+	Key   string   `json:"key"`
+	Value anyValue `json:"value"`
+}
+
+type anyValue struct {
+	StringValue *string       `json:"stringValue,omitempty"`
+	BoolValue   *bool         `json:"boolValue,omitempty"`
+	IntValue    *string       `json:"intValue,omitempty"`
+	DoubleValue *float64      `json:"doubleValue,omitempty"`
+	KvlistValue *keyValueList `json:"kvlistValue,omitempty"`
+}
+
+type keyValueList struct {
+	Values []keyValue `json:"values"`
+}
+
+// keyValues converts attributes, skipping empty ones like slog handlers do.
+func keyValues(attrs []slog.Attr) []keyValue {
+	kvs := make([]keyValue, 0, len(attrs))
+	for _, a := range attrs {
+		a.Value = a.Value.Resolve()
+		if a.Equal(slog.Attr{}) {
+			continue
+		}
+		if a.Value.Kind() == slog.KindGroup && a.Key == "" {
+			kvs = append(kvs, keyValues(a.Value.Group())...)
+			continue
+		}
+		kvs = append(kvs, keyValue{Key: a.Key, Value: toAnyValue(a.Value)})
+	}
+	return kvs
+}
+
+func toAnyValue(v slog.Value) anyValue {
+	switch v.Kind() {
+	case slog.KindBool:
+		b := v.Bool()
+		return anyValue{BoolValue: &b}
+	case slog.KindInt64:
+		i := strconv.FormatInt(v.Int64(), 10)
+		return anyValue{IntValue: &i}
+	case slog.KindUint64:
+		i := strconv.FormatUint(v.Uint64(), 10)
+		return anyValue{IntValue: &i}
+	case slog.KindDuration:
+		i := strconv.FormatInt(int64(v.Duration()), 10)
+		return anyValue{IntValue: &i}
+	case slog.KindFloat64:
+		f := v.Float64()
+		return anyValue{DoubleValue: &f}
+	case slog.KindTime:
+		s := v.Time().Format(time.RFC3339Nano)
+		return anyValue{StringValue: &s}
+	case slog.KindGroup:
+		return anyValue{KvlistValue: &keyValueList{Values: keyValues(v.Group())}}
+	case slog.KindString:
+		s := v.String()
+		return anyValue{StringValue: &s}
+	case slog.KindAny, slog.KindLogValuer:
+	}
+	s := fmt.Sprint(v.Any())
+	return anyValue{StringValue: &s}
+}
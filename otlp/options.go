@@ -0,0 +1,99 @@
+package otlp
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Option configures an Exporter.
+type Option interface {
+	apply(*config)
+}
+
+type optionFunc func(*config)
+
+func (o optionFunc) apply(c *config) {
+	o(c)
+}
+
+// config holds exporter settings.
+type config struct {
+	client        *http.Client      // HTTP client used to post batches
+	headers       map[string]string // extra request headers, e.g. authorization
+	resource      []slog.Attr       // resource attributes
+	level         slog.Leveler      // minimum exported level
+	batchSize     int               // max records per request
+	queueSize     int               // max buffered records
+	flushInterval time.Duration     // max delay before a partial batch is sent
+	maxRetries    int               // retries of a failed batch
+	retryBackoff  time.Duration     // initial retry delay, doubled per attempt
+}
+
+// WithHTTPClient sets the HTTP client used to post batches.
+func WithHTTPClient(client *http.Client) Option {
+	return optionFunc(func(c *config) {
+		c.client = client
+	})
+}
+
+// WithHeaders sets extra request headers, e.g. for collector authentication.
+func WithHeaders(headers map[string]string) Option {
+	return optionFunc(func(c *config) {
+		c.headers = headers
+	})
+}
+
+// WithResource sets the resource attributes, e.g. service.name, of exported records.
+func WithResource(attrs ...slog.Attr) Option {
+	return optionFunc(func(c *config) {
+		c.resource = attrs
+	})
+}
+
+// WithServiceName sets the service.name resource attribute.
+func WithServiceName(name string) Option {
+	return optionFunc(func(c *config) {
+		c.resource = append(c.resource, slog.String("service.name", name))
+	})
+}
+
+// WithLevel sets the minimum level of exported records (default: slog.LevelInfo).
+func WithLevel(level slog.Leveler) Option {
+	return optionFunc(func(c *config) {
+		c.level = level
+	})
+}
+
+// WithBatchSize sets the maximum number of records sent per request (default: 512).
+func WithBatchSize(n int) Option {
+	return optionFunc(func(c *config) {
+		c.batchSize = n
+	})
+}
+
+// WithQueueSize sets the maximum number of buffered records; records beyond it are dropped (default: 2048).
+func WithQueueSize(n int) Option {
+	return optionFunc(func(c *config) {
+		c.queueSize = n
+	})
+}
+
+// WithFlushInterval sets how long a partial batch waits before it is sent (default: 1s).
+// Intervals of zero or less keep the default.
+func WithFlushInterval(d time.Duration) Option {
+	return optionFunc(func(c *config) {
+		if d > 0 {
+			c.flushInterval = d
+		}
+	})
+}
+
+// WithRetry sets how many times a failed batch is retried, and the initial delay between
+// attempts, doubled after each one (default: 5 retries, 500ms).
+func WithRetry(maxRetries int, backoff time.Duration) Option {
+	return optionFunc(func(c *config) {
+		c.maxRetries = maxRetries
+		c.retryBackoff = backoff
+	})
+}
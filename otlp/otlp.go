@@ -0,0 +1,335 @@
+/*
+Package otlp exports log records to an OpenTelemetry Collector over the OTLP/HTTP
+logs protocol (JSON encoding), with resource attributes, batching and retries.
+
+An Exporter is a slog.Handler, so it plugs into the middleware like any other
+handler:
+
+	exp := otlp.New("http://localhost:4318", otlp.WithServiceName("api"))
+	defer exp.Shutdown(context.Background())
+	r.Use(ginslog.SetLogger(ginslog.WithHandler(exp)))
+*/
+package otlp
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// scopeName is the instrumentation scope of exported records.
+const scopeName = "github.com/gin-contrib/slog"
+
+// Exporter is a slog.Handler that batches records and posts them to an OTLP
+// logs endpoint. Handlers derived with WithAttrs and WithGroup share the
+// batching state of the Exporter they derive from.
+type Exporter struct {
+	state *state
+	goas  []groupOrAttrs
+}
+
+// groupOrAttrs is a group or attributes added with WithGroup or WithAttrs.
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// state is the batching state shared by derived handlers.
+type state struct {
+	cfg      config
+	url      string
+	queue    chan logRecord
+	flush    chan chan struct{}
+	done     chan struct{}
+	stopped  sync.Once
+	wg       sync.WaitGroup
+	dropped  atomic.Int64
+	resource resource
+}
+
+/*
+New returns an Exporter posting to the OTLP/HTTP endpoint, e.g.
+"http://localhost:4318"; "/v1/logs" is appended unless already present.
+Call Shutdown to send buffered records before the process exits.
+*/
+func New(endpoint string, opts ...Option) *Exporter {
+	cfg := config{
+		client:        http.DefaultClient,
+		level:         slog.LevelInfo,
+		batchSize:     512,
+		queueSize:     2048,
+		flushInterval: time.Second,
+		maxRetries:    5,
+		retryBackoff:  500 * time.Millisecond,
+	}
+	for _, o := range opts {
+		o.apply(&cfg)
+	}
+
+	url := strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(url, "/v1/logs") {
+		url += "/v1/logs"
+	}
+	s := &state{
+		cfg:      cfg,
+		url:      url,
+		queue:    make(chan logRecord, cfg.queueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+		resource: resource{Attributes: keyValues(cfg.resource)},
+	}
+	s.wg.Add(1)
+	go s.run()
+	return &Exporter{state: s}
+}
+
+// Dropped returns the number of records dropped because the queue was full
+// or a batch still failed after its retries.
+func (e *Exporter) Dropped() int64 {
+	return e.state.dropped.Load()
+}
+
+// Flush sends the buffered records, waiting until they are sent or ctx is done.
+func (e *Exporter) Flush(ctx context.Context) error {
+	ack := make(chan struct{})
+	select {
+	case e.state.flush <- ack:
+	case <-e.state.done:
+		return errors.New("otlp: exporter is shut down")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ack:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Shutdown sends the buffered records and stops the exporter. Records
+// handled afterwards are dropped.
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	s := e.state
+	s.stopped.Do(func() { close(s.done) })
+	finished := make(chan struct{})
+	go func() {
+		s.wg.Wait()
+		close(finished)
+	}()
+	select {
+	case <-finished:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Enabled implements slog.Handler.
+func (e *Exporter) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= e.state.cfg.level.Level()
+}
+
+// Handle implements slog.Handler. The record is queued, never blocking the caller.
+func (e *Exporter) Handle(ctx context.Context, r slog.Record) error {
+	attrs := make([]slog.Attr, 0, r.NumAttrs())
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, a)
+		return true
+	})
+	for i := len(e.goas) - 1; i >= 0; i-- {
+		goa := e.goas[i]
+		if goa.group != "" {
+			if len(attrs) > 0 {
+				attrs = []slog.Attr{{Key: goa.group, Value: slog.GroupValue(attrs...)}}
+			}
+			continue
+		}
+		attrs = append(append([]slog.Attr(nil), goa.attrs...), attrs...)
+	}
+
+	t := r.Time
+	if t.IsZero() {
+		t = time.Now()
+	}
+	lr := logRecord{
+		TimeUnixNano:         strconv.FormatInt(t.UnixNano(), 10),
+		ObservedTimeUnixNano: strconv.FormatInt(time.Now().UnixNano(), 10),
+		SeverityNumber:       severityNumber(r.Level),
+		SeverityText:         severityText(severityNumber(r.Level)),
+		Body:                 anyValue{StringValue: &r.Message},
+		Attributes:           keyValues(attrs),
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		lr.TraceID = sc.TraceID().String()
+		lr.SpanID = sc.SpanID().String()
+	}
+
+	select {
+	case <-e.state.done:
+		e.state.dropped.Add(1)
+		return errors.New("otlp: exporter is shut down")
+	default:
+	}
+	select {
+	case e.state.queue <- lr:
+		return nil
+	default:
+		e.state.dropped.Add(1)
+		return errors.New("otlp: queue is full, record dropped")
+	}
+}
+
+// WithAttrs implements slog.Handler.
+func (e *Exporter) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return e
+	}
+	return e.with(groupOrAttrs{attrs: attrs})
+}
+
+// WithGroup implements slog.Handler.
+func (e *Exporter) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return e
+	}
+	return e.with(groupOrAttrs{group: name})
+}
+
+func (e *Exporter) with(goa groupOrAttrs) *Exporter {
+	goas := make([]groupOrAttrs, len(e.goas), len(e.goas)+1)
+	copy(goas, e.goas)
+	return &Exporter{state: e.state, goas: append(goas, goa)}
+}
+
+// run batches queued records until the exporter is shut down.
+func (s *state) run() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+
+	batch := make([]logRecord, 0, s.cfg.batchSize)
+	send := func() {
+		if len(batch) > 0 {
+			s.send(batch)
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case lr := <-s.queue:
+			batch = append(batch, lr)
+			if len(batch) >= s.cfg.batchSize {
+				send()
+			}
+		case <-ticker.C:
+			send()
+		case ack := <-s.flush:
+			s.drain(&batch, send)
+			send()
+			close(ack)
+		case <-s.done:
+			s.drain(&batch, send)
+			send()
+			return
+		}
+	}
+}
+
+// drain moves every queued record to the batch, sending full batches.
+func (s *state) drain(batch *[]logRecord, send func()) {
+	for {
+		select {
+		case lr := <-s.queue:
+			*batch = append(*batch, lr)
+			if len(*batch) >= s.cfg.batchSize {
+				send()
+			}
+		default:
+			return
+		}
+	}
+}
+
+// send posts a batch, retrying retryable failures with exponential backoff.
+func (s *state) send(batch []logRecord) {
+	body, err := json.Marshal(exportRequest{ResourceLogs: []resourceLogs{{
+		Resource:  s.resource,
+		ScopeLogs: []scopeLogs{{Scope: scope{Name: scopeName}, LogRecords: batch}},
+	}}})
+	if err != nil {
+		s.dropped.Add(int64(len(batch)))
+		return
+	}
+	backoff := s.cfg.retryBackoff
+	for attempt := 0; ; attempt++ {
+		retry, err := s.post(body)
+		if err == nil {
+			return
+		}
+		if !retry || attempt >= s.cfg.maxRetries {
+			s.dropped.Add(int64(len(batch)))
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// post sends one request. It reports whether a failure is worth retrying.
+func (s *state) post(body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range s.cfg.headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := s.cfg.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	_ = resp.Body.Close()
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusTooManyRequests,
+		resp.StatusCode == http.StatusBadGateway,
+		resp.StatusCode == http.StatusServiceUnavailable,
+		resp.StatusCode == http.StatusGatewayTimeout:
+		return true, fmt.Errorf("otlp: %s", resp.Status)
+	default:
+		return false, fmt.Errorf("otlp: %s", resp.Status)
+	}
+}
+
+// severityNumber maps a level to an OTLP severity number: DEBUG is 5, INFO 9,
+// WARN 13, ERROR 17 and FATAL 21, with levels in between kept in range.
+func severityNumber(l slog.Level) int {
+	return min(max(int(l)+9, 1), 24)
+}
+
+// severityNames are the OTLP short names of the severity ranges, four numbers each.
+var severityNames = [...]string{"TRACE", "DEBUG", "INFO", "WARN", "ERROR", "FATAL"}
+
+// severityText returns the OTLP short name of a severity number, e.g. INFO for
+// 9 and INFO2 for 10.
+func severityText(n int) string {
+	name := severityNames[(n-1)/4]
+	if i := (n - 1) % 4; i > 0 {
+		name += strconv.Itoa(i + 1)
+	}
+	return name
+}
@@ -0,0 +1,92 @@
+package slog
+
+import (
+	"cmp"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+// routeOverride is the configuration of the routes under a prefix.
+type routeOverride struct {
+	prefix string
+	opts   []Option
+	cfg    *config
+}
+
+// routeOverrides holds the route overrides, longest prefix first once initialized.
+type routeOverrides []routeOverride
+
+/*
+init derives the configuration of each override from base: a copy of base
+with the override options applied on top. The copy shares the logger, output,
+metrics and sampling state of base, so options configuring them apply to
+base only.
+*/
+func (ro routeOverrides) init(base *config) error {
+	slices.SortStableFunc(ro, func(a, b routeOverride) int {
+		return cmp.Compare(len(b.prefix), len(a.prefix))
+	})
+	for i := range ro {
+		oc := *base
+		oc.routeOverrides = nil
+		// Clip the slices options append to, so the override never writes into base.
+		oc.skippers = slices.Clip(oc.skippers)
+		oc.skipPathRegexps = slices.Clip(oc.skipPathRegexps)
+		oc.sampleRules = slices.Clip(oc.sampleRules)
+		oc.accessHooks = slices.Clip(oc.accessHooks)
+		oc.errorHooks = slices.Clip(oc.errorHooks)
+		oc.reporters = slices.Clip(oc.reporters)
+		oc.observers = slices.Clip(oc.observers)
+		for _, o := range ro[i].opts {
+			o.apply(&oc)
+		}
+		if oc.levels != nil && oc.levels == base.levels {
+			oc.levels = oc.overrideLevels(base)
+		}
+		if err := oc.initRequests(); err != nil {
+			return err
+		}
+		ro[i].cfg = &oc
+	}
+	return nil
+}
+
+// match returns the configuration of the longest prefix covering route, a
+// gin route template, or nil when none does.
+func (ro routeOverrides) match(route string) *config {
+	for i := range ro {
+		p := ro[i].prefix
+		if route == p || (strings.HasPrefix(route, p) && (strings.HasSuffix(p, "/") || route[len(p)] == '/')) {
+			return ro[i].cfg
+		}
+	}
+	return nil
+}
+
+// overrideLevels returns the levels of an override inheriting those of base,
+// as set by New or WithLevels: the levels the override options set replace
+// the inherited ones, the others keep following base.
+func (cfg *config) overrideLevels(base *config) *Levels {
+	l := *base.levels
+	switch {
+	case cfg.levelVar != base.levelVar:
+		l.Default = cfg.levelVar
+	case cfg.defaultLevel != base.defaultLevel:
+		l.Default = newLevelVar(cfg.defaultLevel)
+	}
+	if cfg.clientErrorLevel != base.clientErrorLevel {
+		l.ClientError = newLevelVar(cfg.clientErrorLevel)
+	}
+	if cfg.serverErrorLevel != base.serverErrorLevel {
+		l.ServerError = newLevelVar(cfg.serverErrorLevel)
+	}
+	return &l
+}
+
+// newLevelVar returns a level variable set to level.
+func newLevelVar(level slog.Level) *slog.LevelVar {
+	v := &slog.LevelVar{}
+	v.Set(level)
+	return v
+}
@@ -0,0 +1,283 @@
+package slog
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ANSI color sequences of the pretty console.
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiFaint  = "\x1b[2m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiBlue   = "\x1b[34m"
+	ansiCyan   = "\x1b[36m"
+)
+
+// prettyMessageWidth is the column the message is padded to.
+const prettyMessageWidth = 16
+
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// prettyConsole reports whether the built-in handler is the pretty console:
+// requested by WithPrettyConsole, or by default in gin debug mode when the
+// output is a terminal and no other format is set.
+func (cfg *config) prettyConsole() bool {
+	if cfg.pretty {
+		return true
+	}
+	return !cfg.legacyFields && !cfg.gcp && gin.IsDebugging() && isTerminal(cfg.output)
+}
+
+// prettyColumns holds the keys of the access log fields rendered as columns.
+type prettyColumns struct {
+	status, method, latency, path string
+}
+
+/*
+prettyHandler is a slog.Handler for reading logs in a terminal during local
+development: a colored level, the message padded to a column, the status,
+method, latency and path of access logs as aligned columns, compact
+durations, and the attributes of groups, such as headers, on indented lines.
+*/
+type prettyHandler struct {
+	opts    slog.HandlerOptions
+	columns prettyColumns
+
+	mu     *sync.Mutex
+	w      io.Writer
+	attrs  []slog.Attr // attributes added with WithAttrs, qualified by their groups
+	groups []string    // groups opened with WithGroup
+}
+
+func newPrettyHandler(w io.Writer, opts *slog.HandlerOptions, columns prettyColumns) *prettyHandler {
+	return &prettyHandler{opts: *opts, columns: columns, mu: &sync.Mutex{}, w: w}
+}
+
+// Enabled implements slog.Handler.
+func (h *prettyHandler) Enabled(_ context.Context, level slog.Level) bool {
+	minLevel := slog.LevelInfo
+	if h.opts.Level != nil {
+		minLevel = h.opts.Level.Level()
+	}
+	return level >= minLevel
+}
+
+// Handle implements slog.Handler.
+func (h *prettyHandler) Handle(_ context.Context, r slog.Record) error {
+	attrs := slices.Clip(h.attrs)
+	r.Attrs(func(a slog.Attr) bool {
+		attrs = append(attrs, h.qualify(a))
+		return true
+	})
+
+	var buf bytes.Buffer
+	if !r.Time.IsZero() {
+		buf.WriteString(ansiFaint + r.Time.Format(time.TimeOnly+".000") + ansiReset + " ")
+	}
+	buf.WriteString(levelColor(r.Level) + padRight(levelName(r.Level), 5) + ansiReset + " ")
+	buf.WriteString(padRight(r.Message, prettyMessageWidth))
+
+	attrs = h.writeColumns(&buf, attrs)
+	var groups []slog.Attr
+	for _, a := range attrs {
+		// Empty strings, such as a missing query or referer, are left out.
+		if a = h.replace(nil, a); a.Equal(slog.Attr{}) || a.Value.Equal(slog.StringValue("")) {
+			continue
+		}
+		if a.Value.Kind() == slog.KindGroup {
+			groups = append(groups, a)
+			continue
+		}
+		buf.WriteString(" " + ansiFaint + a.Key + "=" + ansiReset + prettyValue(a.Value))
+	}
+	buf.WriteByte('\n')
+	for _, g := range groups {
+		writePrettyGroup(&buf, g, "    ")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// writeColumns writes the status, method, latency and path columns of an
+// access log and returns the other attributes. Records without a status are
+// written without columns.
+func (h *prettyHandler) writeColumns(buf *bytes.Buffer, attrs []slog.Attr) []slog.Attr {
+	i := slices.IndexFunc(attrs, func(a slog.Attr) bool { return a.Key == h.columns.status })
+	if i < 0 {
+		return attrs
+	}
+	cols := map[string]slog.Value{}
+	rest := make([]slog.Attr, 0, len(attrs))
+	for _, a := range attrs {
+		switch a.Key {
+		case h.columns.status, h.columns.method, h.columns.latency, h.columns.path:
+			cols[a.Key] = a.Value.Resolve()
+		default:
+			rest = append(rest, a)
+		}
+	}
+	status, color := cols[h.columns.status], ""
+	if status.Kind() == slog.KindInt64 {
+		color = statusColor(int(status.Int64()))
+	}
+	buf.WriteString(" " + color + status.String() + ansiReset)
+	buf.WriteString(" " + ansiBold + padRight(cols[h.columns.method].String(), 7) + ansiReset)
+	buf.WriteString(" " + padLeft(prettyValue(cols[h.columns.latency]), 8))
+	buf.WriteString(" " + cols[h.columns.path].String())
+	return rest
+}
+
+// WithAttrs implements slog.Handler.
+func (h *prettyHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	nh := *h
+	nh.attrs = slices.Clip(h.attrs)
+	for _, a := range attrs {
+		nh.attrs = append(nh.attrs, h.qualify(a))
+	}
+	return &nh
+}
+
+// WithGroup implements slog.Handler.
+func (h *prettyHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	nh := *h
+	nh.groups = append(slices.Clip(h.groups), name)
+	return &nh
+}
+
+// qualify prefixes the key of a with the groups opened by WithGroup.
+func (h *prettyHandler) qualify(a slog.Attr) slog.Attr {
+	if len(h.groups) > 0 {
+		a.Key = strings.Join(h.groups, ".") + "." + a.Key
+	}
+	return a
+}
+
+// replace applies the ReplaceAttr option, if any, to scalar attributes.
+func (h *prettyHandler) replace(groups []string, a slog.Attr) slog.Attr {
+	a.Value = a.Value.Resolve()
+	if h.opts.ReplaceAttr == nil || a.Value.Kind() == slog.KindGroup {
+		return a
+	}
+	return h.opts.ReplaceAttr(groups, a)
+}
+
+// writePrettyGroup writes a group attribute and its members on indented lines.
+func writePrettyGroup(buf *bytes.Buffer, g slog.Attr, indent string) {
+	buf.WriteString(indent + ansiFaint + g.Key + ":" + ansiReset + "\n")
+	for _, a := range g.Value.Group() {
+		a.Value = a.Value.Resolve()
+		if a.Value.Kind() == slog.KindGroup {
+			writePrettyGroup(buf, a, indent+"  ")
+			continue
+		}
+		buf.WriteString(indent + "  " + ansiFaint + a.Key + ": " + ansiReset + prettyValue(a.Value) + "\n")
+	}
+}
+
+// prettyValue formats v, with durations in a compact form.
+func prettyValue(v slog.Value) string {
+	if v.Kind() == slog.KindDuration {
+		return compactDuration(v.Duration())
+	}
+	s := v.String()
+	if s == "" || strings.ContainsAny(s, " \t\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// compactDuration formats d with three significant digits, e.g. 1.23ms or 456µs.
+func compactDuration(d time.Duration) string {
+	unit, scale := "ns", time.Duration(1)
+	switch abs := max(d, -d); {
+	case abs >= time.Second:
+		unit, scale = "s", time.Second
+	case abs >= time.Millisecond:
+		unit, scale = "ms", time.Millisecond
+	case abs >= time.Microsecond:
+		unit, scale = "µs", time.Microsecond
+	}
+	v := float64(d) / float64(scale)
+	prec := 0
+	switch abs := max(v, -v); {
+	case scale == 1:
+	case abs < 10:
+		prec = 2
+	case abs < 100:
+		prec = 1
+	}
+	return strconv.FormatFloat(v, 'f', prec, 64) + unit
+}
+
+// levelColor returns the color of a level.
+func levelColor(l slog.Level) string {
+	switch {
+	case l >= slog.LevelError:
+		return ansiRed
+	case l >= slog.LevelWarn:
+		return ansiYellow
+	case l >= slog.LevelInfo:
+		return ansiGreen
+	default:
+		return ansiBlue
+	}
+}
+
+// statusColor returns the color of a response status.
+func statusColor(status int) string {
+	switch {
+	case status >= 500:
+		return ansiRed
+	case status >= 400:
+		return ansiYellow
+	case status >= 300:
+		return ansiCyan
+	default:
+		return ansiGreen
+	}
+}
+
+func padRight(s string, n int) string {
+	if w := len([]rune(s)); w < n {
+		return s + strings.Repeat(" ", n-w)
+	}
+	return s
+}
+
+func padLeft(s string, n int) string {
+	if w := len([]rune(s)); w < n {
+		return strings.Repeat(" ", n-w) + s
+	}
+	return s
+}
@@ -0,0 +1,82 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// logLimitSummaryInterval is the minimum time between two dropped log summaries.
+const logLimitSummaryInterval = 10 * time.Second
+
+/*
+logLimiter is a token bucket bounding the access log records written per
+second, with a burst of one second worth of records. It counts the records it
+drops and, once per logLimitSummaryInterval, emits a summary of them with the
+next request.
+*/
+type logLimiter struct {
+	rate float64
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	start   time.Time // start of the summary window
+	dropped int
+}
+
+func newLogLimiter(perSecond int) *logLimiter {
+	return &logLimiter{rate: float64(perSecond), tokens: float64(perSecond)}
+}
+
+// allow reports whether a record may be written at now, and emits the
+// summary of the dropped records through l when due.
+func (b *logLimiter) allow(l *slog.Logger, level slog.Level, now time.Time) bool {
+	b.mu.Lock()
+	if b.start.IsZero() {
+		b.start, b.last = now, now
+	}
+	// Concurrent requests end out of order: a late one must not refill
+	// negatively or move the bucket back in time.
+	if elapsed := now.Sub(b.last); elapsed > 0 {
+		b.tokens = min(b.rate, b.tokens+elapsed.Seconds()*b.rate)
+		b.last = now
+	}
+	ok := b.tokens >= 1
+	if ok {
+		b.tokens--
+	} else {
+		b.dropped++
+	}
+	if now.Sub(b.start) < logLimitSummaryInterval || b.dropped == 0 {
+		b.mu.Unlock()
+		return ok
+	}
+	start, dropped := b.start, b.dropped
+	b.start, b.dropped = now, 0
+	b.mu.Unlock()
+
+	l.LogAttrs(context.Background(), level, "Access logs dropped",
+		slog.Time("window_start", start),
+		slog.Time("window_end", now),
+		slog.Int("dropped", dropped),
+	)
+	return ok
+}
+
+// flush emits the summary of the records dropped since the last one, if any, e.g. on shutdown.
+func (b *logLimiter) flush(l *slog.Logger, level slog.Level, now time.Time) {
+	b.mu.Lock()
+	start, dropped := b.start, b.dropped
+	b.start, b.dropped = now, 0
+	b.mu.Unlock()
+	if dropped == 0 {
+		return
+	}
+	l.LogAttrs(context.Background(), level, "Access logs dropped",
+		slog.Time("window_start", start),
+		slog.Time("window_end", now),
+		slog.Int("dropped", dropped),
+	)
+}
@@ -0,0 +1,41 @@
+package slog
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// panicInfo holds a recovered panic value and the stack at the point of recovery.
+type panicInfo struct {
+	value any
+	stack []byte
+}
+
+// defaultPanicFormatter renders a panic value the same way fmt does.
+func defaultPanicFormatter(v any) slog.Value {
+	return slog.StringValue(fmt.Sprintf("%v", v))
+}
+
+// runHandlers calls c.Next. When recovery is enabled, a panic raised by the
+// remaining handlers is recovered, the request is aborted with a 500 status
+// (unless a response was already written) and the panic is returned.
+func runHandlers(c *gin.Context, recovery bool) (p *panicInfo) {
+	if recovery {
+		defer func() {
+			if v := recover(); v != nil {
+				p = &panicInfo{value: v, stack: debug.Stack()}
+				if c.Writer.Written() {
+					c.Abort()
+				} else {
+					c.AbortWithStatus(http.StatusInternalServerError)
+				}
+			}
+		}()
+	}
+	c.Next()
+	return nil
+}
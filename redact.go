@@ -0,0 +1,165 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+/*
+RedactRule selects sensitive values for WithRedaction: every value of an
+attribute with a given key, or the parts of string values matching a pattern.
+Rules apply to all logged attributes, at any depth, including headers, query
+strings, captured bodies and attributes added by handlers.
+*/
+type RedactRule struct {
+	key     string              // lower-case attribute key, if a key rule
+	pattern *regexp.Regexp      // matched within string values, if a pattern rule
+	valid   func(s string) bool // filters pattern matches, if set
+}
+
+// RedactKey returns a rule redacting the values of attributes named key, case-insensitively.
+func RedactKey(key string) RedactRule {
+	return RedactRule{key: strings.ToLower(key)}
+}
+
+// RedactPattern returns a rule redacting the parts of string values matching re.
+func RedactPattern(re *regexp.Regexp) RedactRule {
+	return RedactRule{pattern: re}
+}
+
+// Built-in redaction rules.
+var (
+	// RedactBearerTokens redacts bearer credentials such as "Bearer eyJhbGciOi...".
+	RedactBearerTokens = RedactPattern(regexp.MustCompile(`(?i)\bbearer\s+[A-Za-z0-9\-._~+/]+=*`))
+	// RedactEmails redacts e-mail addresses.
+	RedactEmails = RedactPattern(regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`))
+	// RedactCreditCards redacts card numbers of 13 to 19 digits, optionally separated
+	// by spaces or dashes, that pass the Luhn check.
+	RedactCreditCards = RedactRule{pattern: regexp.MustCompile(`\b\d(?:[ \-]?\d){12,18}\b`), valid: luhnValid}
+)
+
+// luhnValid reports whether the digits of s pass the Luhn checksum.
+func luhnValid(s string) bool {
+	sum, double := 0, false
+	for i := len(s) - 1; i >= 0; i-- {
+		d := int(s[i] - '0')
+		if d < 0 || d > 9 {
+			continue
+		}
+		if double {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		double = !double
+	}
+	return sum%10 == 0
+}
+
+// redactor applies redaction rules.
+type redactor struct {
+	keys     map[string]struct{}
+	patterns []RedactRule
+}
+
+func newRedactor(rules []RedactRule) *redactor {
+	r := &redactor{keys: map[string]struct{}{}}
+	for _, rule := range rules {
+		if rule.pattern != nil {
+			r.patterns = append(r.patterns, rule)
+		} else {
+			r.keys[rule.key] = struct{}{}
+		}
+	}
+	return r
+}
+
+// string redacts the pattern matches in s.
+func (r *redactor) string(s string) string {
+	for _, rule := range r.patterns {
+		s = rule.pattern.ReplaceAllStringFunc(s, func(m string) string {
+			if rule.valid != nil && !rule.valid(m) {
+				return m
+			}
+			return redactedValue
+		})
+	}
+	return s
+}
+
+// attr returns a with its redacted values.
+func (r *redactor) attr(a slog.Attr) slog.Attr {
+	if _, ok := r.keys[strings.ToLower(a.Key)]; ok {
+		return slog.String(a.Key, redactedValue)
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, r.string(v.String()))
+	case slog.KindGroup:
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(r.attrs(v.Group())...)}
+	case slog.KindAny:
+		if s, ok := v.Any().([]string); ok {
+			out := make([]string, len(s))
+			for i := range s {
+				out[i] = r.string(s[i])
+			}
+			return slog.Any(a.Key, out)
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
+
+func (r *redactor) attrs(attrs []slog.Attr) []slog.Attr {
+	out := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		out[i] = r.attr(a)
+	}
+	return out
+}
+
+// record returns a copy of rec with its redacted message and attributes.
+func (r *redactor) record(rec slog.Record) slog.Record {
+	nr := slog.NewRecord(rec.Time, rec.Level, r.string(rec.Message), rec.PC)
+	rec.Attrs(func(a slog.Attr) bool {
+		nr.AddAttrs(r.attr(a))
+		return true
+	})
+	return nr
+}
+
+// redactHandler redacts records, and attributes added with Logger.With,
+// before passing them on.
+type redactHandler struct {
+	next slog.Handler
+	r    *redactor
+}
+
+// Enabled implements slog.Handler.
+func (h *redactHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler.
+func (h *redactHandler) Handle(ctx context.Context, rec slog.Record) error {
+	return h.next.Handle(ctx, h.r.record(rec))
+}
+
+// WithAttrs implements slog.Handler.
+func (h *redactHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return &redactHandler{next: h.next.WithAttrs(h.r.attrs(attrs)), r: h.r}
+}
+
+// WithGroup implements slog.Handler.
+func (h *redactHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &redactHandler{next: h.next.WithGroup(name), r: h.r}
+}
@@ -0,0 +1,58 @@
+package slog
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+const requestIDKey = "_gin-contrib/slog/request_id_"
+
+// maxRequestIDLength bounds incoming request ids accepted from clients.
+const maxRequestIDLength = 128
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+// validRequestID reports whether an incoming id is short and printable ASCII,
+// so clients cannot inject arbitrary content into logs.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// RequestID returns the request id assigned by a middleware configured with
+// WithRequestID, or an empty string.
+func RequestID(c *gin.Context) string {
+	return c.GetString(requestIDKey)
+}
+
+// requestID reads the request id from the header, or generates one.
+func requestID(c *gin.Context, header string, generate func() string) string {
+	if id := c.GetHeader(header); validRequestID(id) {
+		return id
+	}
+	return generate()
+}
+
+// setRequestID reads or generates the request id, echoes it on the response and stores it in c.
+func setRequestID(c *gin.Context, header string, generate func() string) string {
+	id := requestID(c, header, generate)
+	c.Header(header, id)
+	c.Set(requestIDKey, id)
+	return id
+}
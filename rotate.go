@@ -0,0 +1,185 @@
+package slog
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// backupTimeFormat is the timestamp layout, in UTC, of rotated file names.
+const backupTimeFormat = "2006-01-02T15-04-05.000"
+
+// rotation holds the settings of WithRotatingFile.
+type rotation struct {
+	path       string
+	maxSize    int64 // bytes, 0 disables size-based rotation
+	maxBackups int   // 0 keeps every backup
+	maxAge     time.Duration
+	compress   bool
+}
+
+// rotatingFile is a goroutine-safe io.Writer appending to a file that is
+// rotated once it would exceed maxSize. Rotated files are renamed to
+// name-<timestamp>.ext, optionally gzipped, and pruned by count and age.
+type rotatingFile struct {
+	rotation
+	mu   sync.Mutex
+	file *os.File
+	size int64
+
+	cleanupMu sync.Mutex     // runs the cleanups one at a time
+	cleanups  sync.WaitGroup // pending cleanups, awaited by Close
+}
+
+func openRotatingFile(r rotation) (*rotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o750); err != nil {
+		return nil, err
+	}
+	f := &rotatingFile{rotation: r}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return err
+	}
+	f.file, f.size = file, info.Size()
+	return nil
+}
+
+// Write implements io.Writer.
+func (f *rotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the current file, once the pending cleanups are done.
+func (f *rotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.cleanups.Wait()
+	return f.file.Close()
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	backup := f.backupName(time.Now().UTC())
+	if err := os.Rename(f.path, backup); err != nil {
+		return err
+	}
+	if err := f.open(); err != nil {
+		return err
+	}
+	f.cleanups.Add(1)
+	go f.cleanup(backup)
+	return nil
+}
+
+// backupName returns the name of a backup rotated at t, in UTC, moved past
+// the stamps of existing backups so a rotation never overwrites one.
+func (f *rotatingFile) backupName(t time.Time) string {
+	ext := filepath.Ext(f.path)
+	for {
+		backup := strings.TrimSuffix(f.path, ext) + "-" + t.Format(backupTimeFormat) + ext
+		if !fileExists(backup) && !fileExists(backup+".gz") {
+			return backup
+		}
+		t = t.Add(time.Millisecond)
+	}
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// cleanup compresses the new backup and removes backups beyond the limits.
+func (f *rotatingFile) cleanup(backup string) {
+	defer f.cleanups.Done()
+	f.cleanupMu.Lock()
+	defer f.cleanupMu.Unlock()
+	if f.compress {
+		if err := gzipFile(backup); err == nil {
+			_ = os.Remove(backup)
+		}
+	}
+
+	ext := filepath.Ext(f.path)
+	prefix := filepath.Base(strings.TrimSuffix(f.path, ext)) + "-"
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return
+	}
+	type backupFile struct {
+		name string
+		t    time.Time
+	}
+	var backups []backupFile
+	for _, e := range entries {
+		name := e.Name()
+		stamp, ok := strings.CutPrefix(name, prefix)
+		if !ok || e.IsDir() {
+			continue
+		}
+		stamp = strings.TrimSuffix(strings.TrimSuffix(stamp, ".gz"), ext)
+		if t, err := time.Parse(backupTimeFormat, stamp); err == nil {
+			backups = append(backups, backupFile{name: name, t: t})
+		}
+	}
+	slices.SortFunc(backups, func(a, b backupFile) int { return b.t.Compare(a.t) })
+
+	for i, b := range backups {
+		tooMany := f.maxBackups > 0 && i >= f.maxBackups
+		tooOld := f.maxAge > 0 && time.Since(b.t) > f.maxAge
+		if tooMany || tooOld {
+			_ = os.Remove(filepath.Join(filepath.Dir(f.path), b.name))
+		}
+	}
+}
+
+func gzipFile(path string) error {
+	src, err := os.Open(filepath.Clean(path))
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	if _, err := io.Copy(zw, src); err != nil {
+		_ = zw.Close()
+		_ = dst.Close()
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		_ = dst.Close()
+		return err
+	}
+	return dst.Close()
+}
@@ -0,0 +1,78 @@
+package slog
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+RouteLoggers caches child loggers carrying method and route attributes per
+registered route, so the middleware reuses them instead of deriving a logger
+on every request. Install it with WithRouteLoggers and call Warm once routes
+are registered to build every logger ahead of traffic:
+
+	pool := slog.NewRouteLoggers()
+	r.Use(slog.SetLogger(slog.WithRouteLoggers(pool)))
+	r.GET("/users/:id", handler)
+	pool.Warm(r)
+*/
+type RouteLoggers struct {
+	mu      sync.Mutex
+	base    *slog.Logger
+	names   FieldNames      // renamed attribute keys
+	pending []gin.RouteInfo // routes warmed before the base logger was bound
+
+	loggers sync.Map // method + " " + route -> *slog.Logger
+}
+
+// NewRouteLoggers returns an empty route logger pool.
+func NewRouteLoggers() *RouteLoggers {
+	return &RouteLoggers{}
+}
+
+// Warm pre-creates loggers for every route registered on engine.
+func (p *RouteLoggers) Warm(engine *gin.Engine) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.base == nil {
+		p.pending = append(p.pending, engine.Routes()...)
+		return
+	}
+	for _, r := range engine.Routes() {
+		p.build(r.Method, r.Path)
+	}
+}
+
+// bind sets the base logger the pooled loggers derive from.
+func (p *RouteLoggers) bind(base *slog.Logger, names FieldNames) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.base = base
+	p.names = names
+	for _, r := range p.pending {
+		p.build(r.Method, r.Path)
+	}
+	p.pending = nil
+}
+
+func (p *RouteLoggers) build(method, route string) *slog.Logger {
+	l := p.base.With(slog.String(p.names.key(FieldMethod), method), slog.String(p.names.key(FieldRoute), route))
+	actual, _ := p.loggers.LoadOrStore(method+" "+route, l)
+	return actual.(*slog.Logger)
+}
+
+// get returns the logger for the route, creating it on first use. Requests
+// matching no route share the base logger.
+func (p *RouteLoggers) get(method, route string) (*slog.Logger, bool) {
+	if route == "" {
+		return p.base, false
+	}
+	if l, ok := p.loggers.Load(method + " " + route); ok {
+		return l.(*slog.Logger), true
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.build(method, route), true
+}
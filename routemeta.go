@@ -0,0 +1,9 @@
+package slog
+
+import "log/slog"
+
+// routeMeta is the message and static attributes set for a route by WithRouteMeta.
+type routeMeta struct {
+	message string
+	attrs   []slog.Attr
+}
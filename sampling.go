@@ -0,0 +1,48 @@
+package slog
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sampleRule applies a sample rate to paths matching a regular expression.
+type sampleRule struct {
+	re   *regexp.Regexp
+	rate float64
+}
+
+// sampleRate returns the sample rate configured for the request. Routes
+// (c.FullPath(), or the WithRouteNormalizer result) take precedence over regexp rules matched against
+// the URL path, which take precedence over the global rate. Requests matching
+// no route (e.g. served by NoRoute handlers) look up their URL path instead.
+func sampleRate(cfg *config, route, path string) float64 {
+	key := route
+	if key == "" {
+		key = path
+	}
+	if rate, ok := cfg.pathSampleRates[key]; ok {
+		return rate
+	}
+	for _, r := range cfg.sampleRules {
+		if r.re.MatchString(path) {
+			return r.rate
+		}
+	}
+	return cfg.sampleRate
+}
+
+// shouldSample reports whether a request should be logged. Client and server
+// errors are always logged; other requests are kept with their configured
+// sample rate and when the custom sampler, if any, agrees.
+func shouldSample(cfg *config, c *gin.Context, route, path string) bool {
+	if c.Writer.Status() >= http.StatusBadRequest {
+		return true
+	}
+	if rate := sampleRate(cfg, route, path); rate < 1 && rand.Float64() >= rate { //nolint:gosec // sampling does not need a CSPRNG
+		return false
+	}
+	return cfg.sampler == nil || cfg.sampler(c)
+}
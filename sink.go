@@ -0,0 +1,101 @@
+package slog
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+)
+
+// Sink is a log destination with its own minimum level.
+type Sink struct {
+	Handler slog.Handler
+	// Level is the minimum level of records written to Handler. When nil,
+	// the handler's own Enabled method decides.
+	Level slog.Leveler
+}
+
+func (s Sink) enabled(ctx context.Context, level slog.Level) bool {
+	if isDebugContext(ctx) {
+		return true
+	}
+	if s.Level == nil {
+		return s.Handler.Enabled(ctx, level)
+	}
+	return level >= s.Level.Level()
+}
+
+// WeightedWriter is a text log destination with its own minimum level.
+type WeightedWriter struct {
+	Writer io.Writer
+	// Level is the minimum level of records written to Writer; nil means slog.LevelInfo.
+	Level slog.Leveler
+}
+
+// sink returns a sink writing text records like the built-in handler.
+func (w WeightedWriter) sink() Sink {
+	level := w.Level
+	if level == nil {
+		level = slog.LevelInfo
+	}
+	return Sink{
+		Handler: slog.NewTextHandler(w.Writer, &slog.HandlerOptions{
+			Level:       level,
+			ReplaceAttr: ReplaceLevelNames,
+		}),
+		Level: level,
+	}
+}
+
+// syslogTarget is the syslog daemon set by WithSyslog.
+type syslogTarget struct {
+	network string
+	addr    string
+	tag     string
+}
+
+// multiHandler fans records out to sinks, evaluating each sink's level independently.
+type multiHandler struct {
+	sinks []Sink
+}
+
+// Enabled implements slog.Handler.
+func (h *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, s := range h.sinks {
+		if s.enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+// Handle implements slog.Handler.
+func (h *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, s := range h.sinks {
+		if s.enabled(ctx, r.Level) {
+			if err := s.Handler.Handle(ctx, r.Clone()); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sinks := make([]Sink, len(h.sinks))
+	for i, s := range h.sinks {
+		sinks[i] = Sink{Handler: s.Handler.WithAttrs(attrs), Level: s.Level}
+	}
+	return &multiHandler{sinks: sinks}
+}
+
+// WithGroup implements slog.Handler.
+func (h *multiHandler) WithGroup(name string) slog.Handler {
+	sinks := make([]Sink, len(h.sinks))
+	for i, s := range h.sinks {
+		sinks[i] = Sink{Handler: s.Handler.WithGroup(name), Level: s.Level}
+	}
+	return &multiHandler{sinks: sinks}
+}
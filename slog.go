@@ -1,10 +1,14 @@
 package slog
 
 import (
+	"cmp"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
+	"net/netip"
 	"os"
 	"regexp"
 	"strings"
@@ -33,27 +37,470 @@ and returns a boolean indicating whether to skip the middleware for the given co
 */
 type Skipper func(c *gin.Context) bool
 
+// HandlerMiddleware decorates a slog.Handler, e.g. to inject attributes, filter or fan out records.
+type HandlerMiddleware func(slog.Handler) slog.Handler
+
+// layout renders the request fields of an access record as attributes, replacing
+// the built-in status, method, path, ... fields.
+type layout func(rec *AccessRecord) []slog.Attr
+
+// AccessHook receives the typed record of a logged request.
+type AccessHook func(c *gin.Context, rec *AccessRecord)
+
+// ErrorHook receives the record of a request logged at or above the server error level.
+type ErrorHook func(c *gin.Context, rec slog.Record)
+
+// LogObserver receives every access log record after it is emitted. Records of
+// requests dropped by sampling are passed too, with "sampled_out": true.
+type LogObserver func(c *gin.Context, rec slog.Record)
+
+// Reporter receives the level, message and attributes of a logged request, e.g.
+// to forward 5xx responses and panics to an error tracker.
+type Reporter func(c *gin.Context, level slog.Level, msg string, attrs []slog.Attr)
+
+// reporter is a Reporter with its threshold.
+type reporter struct {
+	level slog.Level
+	fn    Reporter
+}
+
+// StatusClass groups HTTP status codes for settings that apply per class.
+type StatusClass int
+
+const (
+	// StatusClassSuccess covers status codes below 400.
+	StatusClassSuccess StatusClass = iota
+	// StatusClassClientError covers status codes 400-499.
+	StatusClassClientError
+	// StatusClassServerError covers status codes 500 and above.
+	StatusClassServerError
+)
+
 // config holds logger middleware settings.
 type config struct {
-	logger                    Fn                    // custom logger function
-	context                   EventFn               // gin.Context to log context
-	utc                       bool                  // use UTC time
-	skipPath                  []string              // exact path to skip
-	skipPathRegexps           []*regexp.Regexp      // regex path to skip
-	skip                      Skipper               // function to skip logging
-	output                    io.Writer             // log output writer
-	defaultLevel              slog.Level            // <400 log level
-	clientErrorLevel          slog.Level            // 400-499 log level
-	serverErrorLevel          slog.Level            // >=500 log level
-	pathLevels                map[string]slog.Level // per-path <400 log level
-	message                   string                // log message
-	specificLevelByStatusCode map[int]slog.Level    // status-specific log level
-	withRequestHeader         bool                  // log all headers
-	hiddenRequestHeaders      map[string]struct{}   // hidden headers (lower-case)
+	logger                    Fn                          // custom logger function
+	context                   EventFn                     // gin.Context to log context
+	utc                       bool                        // use UTC time
+	skipPath                  []string                    // exact path to skip
+	skipPathRegexps           []*regexp.Regexp            // regex path to skip
+	skip                      Skipper                     // function to skip logging
+	skippers                  []Skipper                   // built-in skip functions
+	filters                   Filter                      // WithFilters, evaluated after the request
+	output                    io.Writer                   // log output writer
+	outputSet                 bool                        // output set by WithWriter
+	testOutput                io.Writer                   // default output in gin test mode
+	handler                   slog.Handler                // custom handler, overrides output
+	baseLogger                *slog.Logger                // custom base logger, overrides handler
+	routeLoggers              *RouteLoggers               // pooled per-route child loggers
+	keyedLoggers              *keyedLoggers               // cached per-key derived loggers
+	routeOverrides            routeOverrides              // per-route-group option overrides
+	defaultLevel              slog.Level                  // <400 log level
+	defaultLeveler            slog.Leveler                // effective <400 log level
+	levelVar                  *slog.LevelVar              // runtime-adjustable <400 log level
+	startupLevel              slog.Level                  // <400 log level right after start
+	startupDuration           time.Duration               // how long startupLevel applies
+	clientErrorLevel          slog.Level                  // 400-499 log level
+	serverErrorLevel          slog.Level                  // >=500 log level
+	redirectLevel             slog.Leveler                // 300-399 log level, if set
+	levels                    *Levels                     // runtime-adjustable levels
+	clientErrorLeveler        slog.Leveler                // effective 400-499 log level
+	serverErrorLeveler        slog.Leveler                // effective >=500 log level
+	pathLevels                map[string]slog.Level       // per-path <400 log level
+	routeLevels               map[string]slog.Level       // per-route-template <400 log level
+	methodLevels              map[string]slog.Level       // per-method <400 log level (upper-case)
+	levelFile                 *levelFile                  // hot-reloaded path, route and status levels
+	levelByCIDR               map[string]slog.Level       // per-client-network <400 log level
+	cidrLevels                []cidrLevel                 // parsed levelByCIDR, most specific first
+	trustedProxies            []string                    // proxies trusted for X-Forwarded-For
+	trustedPrefixes           []netip.Prefix              // parsed trustedProxies
+	ipResolver                func(*gin.Context) string   // custom client IP resolution
+	forwardedChain            bool                        // log the forwarding chain
+	headerAttrs               []headerAttr                // headers logged as top-level attributes
+	forwarded                 bool                        // log the Forwarded client hop
+	proxyChain                bool                        // log every Forwarded hop
+	connInfo                  bool                        // log protocol, host and scheme
+	requestSize               bool                        // log declared and read request body sizes
+	contentTypes              bool                        // log request and response content types
+	multipartMax              int64                       // multipart body bytes inspected for part metadata
+	streamingMetrics          bool                        // log time to first byte and stream duration
+	abortDetection            bool                        // mark requests the client aborted
+	abortLevel                slog.Level                  // level of aborted requests
+	startLog                  bool                        // log a record when requests start
+	startLevel                slog.Level                  // level of request start records
+	audit                     *auditLog                   // audit stream of selected requests
+	staticAttrs               []slog.Attr                 // attributes of the base logger
+	hostMetadata              bool                        // add host attributes to the base logger
+	latencyFormat             LatencyFormat               // rendering of the latency attribute
+	timeFormat                string                      // time layout of the built-in handler
+	timeKey                   string                      // time key of the built-in handler
+	handlerOptions            *slog.HandlerOptions        // options of the built-in handler
+	handlerMiddleware         []HandlerMiddleware         // decorators of the base handler, outermost first
+	group                     string                      // group of the access log attributes
+	graphqlPath               string                      // path of the GraphQL endpoint
+	curl                      *curlRepro                  // reproduction command of failed requests
+	metrics                   MetricsRecorder             // recorder of the request metrics
+	anonymizeIP               bool                        // truncate logged client addresses
+	ipHashSalt                []byte                      // salt of hashed client addresses, nil logs them
+	geoIP                     GeoIPFunc                   // client IP enrichment
+	principal                 PrincipalFunc               // authenticated subject of requests
+	tenant                    func(*gin.Context) string   // tenant of requests
+	message                   string                      // log message
+	messages                  map[StatusClass]string      // per-status-class log message
+	specificLevelByStatusCode map[int]slog.Level          // status-specific log level
+	withRequestHeader         bool                        // log all headers
+	requestBodyMax            int                         // max captured request body bytes
+	requestBodyTypes          []string                    // content types eligible for body capture
+	hiddenRequestHeaders      map[string]struct{}         // hidden headers (lower-case)
+	headerMaskPrefix          int                         // kept bytes of masked hidden headers, <0 drops them
+	withURI                   bool                        // log the reconstructed request target
+	redactedQueryParams       map[string]struct{}         // query parameters redacted in uri (lower-case)
+	withTraceID               bool                        // log OpenTelemetry trace and span ids
+	requestIDHeader           string                      // request id header, enables request ids
+	requestIDGenerator        func() string               // generates missing request ids
+	traceFormats              []TraceFormat               // trace headers to parse, in order
+	responseBodyMax           int                         // max captured error response body bytes
+	bodyDigest                DigestAlgorithm             // hash of the request body, if any
+	responseDigest            bool                        // hash the response body too
+	errorTypes                gin.ErrorType               // gin error types to log
+	errorTypeAttrs            bool                        // log errors grouped by type
+	errorsInMessage           bool                        // append errors to the message instead of a group
+	errorStackTrace           bool                        // log error stack frames on 5xx
+	latencyBuckets            []time.Duration             // sorted latency bucket bounds
+	sampleRate                float64                     // global sample rate
+	sampler                   func(*gin.Context) bool     // custom sampler, true keeps the record
+	pathSampleRates           map[string]float64          // per-route sample rate
+	sampleRules               []sampleRule                // per-regexp sample rate
+	notFoundInterval          time.Duration               // route-not-found summary interval
+	notFoundTopN              int                         // paths reported per summary
+	maxLogsPerSecond          int                         // access log records written per second
+	historySize               int                         // access records kept for HistoryHandler
+	levelMapper               func(slog.Level) slog.Level // maps levels right before handling
+	conflictPolicy            ConflictPolicy              // duplicate attribute key resolution
+	errorWriter               *ErrorWriter                // captures gin error output per request
+	recovery                  bool                        // recover panics in handlers
+	panicFormatter            func(any) slog.Value        // renders recovered panic values
+	debugRequests             *DebugRequests              // request ids to fully capture once
+	levelHeader               *levelHeader                // trusted header requesting a full capture
+	skipBareRequests          bool                        // skip requests without Request or URL
+	forceLog                  *forceLog                   // header bypassing sampling and skips
+	slowThreshold             time.Duration               // latency above which a request is slow
+	slowLevel                 slog.Level                  // minimum level of slow requests
+	latencyBreakdown          bool                        // split latency into phases
+	largeResponse             int64                       // body size above which a response is large
+	largeResponseLevel        slog.Level                  // minimum level of large responses
+	accessHooks               []AccessHook                // receive logged access records
+	errorHooks                []ErrorHook                 // receive records at server error level
+	reporters                 []reporter                  // receive records at or above their level
+	observers                 []LogObserver               // receive every record, sampled out ones flagged
+	errorRate                 *errorRate                  // 5xx ratio alert over a sliding window
+	detachContext             bool                        // deliver records with a non-canceled context
+	detachTimeout             time.Duration               // deadline of the detached context
+	nestedPolicy              NestedPolicy                // which nested instance logs
+	routeNormalizer           func(*gin.Context) string   // maps requests to bounded route keys
+	healthPaths               map[string]struct{}         // health endpoints, failures logged at warn
+	fields                    map[Field]struct{}          // selected built-in fields, nil logs all
+	fieldNames                FieldNames                  // renamed built-in attribute keys
+	costSummary               bool                        // log the cost summary group
+	layout                    layout                      // renders the request fields
+	gcp                       bool                        // Cloud Logging JSON by default
+	legacyFields              bool                        // gin-contrib/logger JSON by default
+	otelSemConv               bool                        // add OpenTelemetry server attributes
+	combinedLog               bool                        // write combined log format lines
+	syslog                    *syslogTarget               // syslog daemon, overrides handler
+	rotation                  *rotation                   // rotating log file, overrides output
+	batchRecords              int                         // records buffered before a write
+	batchInterval             time.Duration               // max time output stays buffered
+	encryptedFields           map[string]struct{}         // attribute keys logged encrypted
+	redactor                  *redactor                   // redacts all logged values
+	contextKeys               []string                    // gin context keys logged as attributes
+	routeMeta                 map[string]routeMeta        // messages and attributes by route
+	deadlineInfo              bool                        // log request context deadline budget
+	errorIDHeader             string                      // response header of 5xx error ids
+	pretty                    bool                        // colored console built-in handler
+	errorsOnly                bool                        // log only failed or escalated requests
+	dryRun                    *Stats                      // count decisions instead of logging
+	minLatency                time.Duration               // requests faster than this are not logged
+	baggage                   bool                        // log OpenTelemetry baggage members
+	baggageKeys               []string                    // baggage members logged, all if empty
+	keyProvider               KeyProvider                 // keys of encrypted fields
+
+	// derived in init
+	skipSet       map[string]struct{} // skipPath as a set
+	bucketLabels  []string            // latency bucket labels
+	notFound      *notFoundSummary    // route-not-found aggregator
+	logLimiter    *logLimiter         // WithMaxLogsPerSecond token bucket
+	history       *requestHistory     // recent logged records, with WithRequestHistory
+	batch         *BatchWriter        // batched output, with WithBatching
+	rotating      *rotatingFile       // WithRotatingFile output, closed by Close
+	combined      *combinedWriter     // combined log format writer
+	hiddenHeaders headerSet           // hiddenRequestHeaders lookup
+	base          *slog.Logger        // base logger
+}
+
+// request holds the state of a single request being logged.
+type request struct {
+	logger      *slog.Logger    // request-scoped logger
+	pooled      bool            // logger carries method and route attrs
+	debug       bool            // full capture, bypasses skip and sampling
+	force       bool            // bypasses skip and sampling
+	sampledOut  bool            // dropped by sampling, built for the observers only
+	start       time.Time       // start of the request
+	end         time.Time       // end of the request, as logged
+	latency     time.Duration   // time taken to serve the request
+	ip          string          // client IP
+	level       slog.Level      // access log level, before WithLevelMapper
+	slow        bool            // latency above the slow threshold
+	large       bool            // body size above the large response threshold
+	route       string          // route template or normalized route
+	tenant      string          // tenant, if attributed
+	requestID   string          // request id, with WithRequestID
+	traceID     string          // trace id, if traced
+	spanID      string          // span id, if traced
+	path        string          // URL path
+	query       string          // raw query
+	reqBody     *bodyCapture    // captured request body
+	reqDigest   *digestReader   // hashed request body
+	respDigest  *digestWriter   // hashed response body
+	multipart   *multipartTee   // multipart part metadata
+	graphql     *graphqlOp      // GraphQL operation, if any
+	hw          hijackWriter    // writer counting hijacked connection bytes
+	stream      *streamWriter   // writer recording the first byte, when measured
+	timer       *writeTimer     // writer timing the body writes, when broken down
+	abort       *abortWriter    // writer recording broken pipes, when detected
+	errorID     *errorIDWriter  // writer assigning 5xx error ids, with WithErrorID
+	aborted     bool            // the client disconnected early
+	access      AccessRecord    // typed access record
+	cost        *cost           // resource usage, when summarized
+	rw          *responseWriter // observing response writer
+	panic       *panicInfo      // recovered panic
+	errorOutput []string        // captured gin error output
 }
 
 const loggerKey = "_gin-contrib/logger_"
 
+// configKey stores the configuration serving the request, for RequestValue.
+const configKey = "_gin-contrib/slog/config_"
+
+// newConfig returns the default configuration with the options applied.
+func newConfig(opts ...Option) *config {
+	cfg := &config{
+		defaultLevel:       slog.LevelInfo,
+		clientErrorLevel:   slog.LevelWarn,
+		serverErrorLevel:   slog.LevelError,
+		output:             os.Stderr,
+		message:            "Request",
+		sampleRate:         1,
+		requestIDGenerator: newUUID,
+		errorTypes:         gin.ErrorTypeAny,
+		panicFormatter:     defaultPanicFormatter,
+		withRequestHeader:  false, // Recommended: enable only in debug/testing, keep disabled by default in production
+		headerMaskPrefix:   -1,
+		hiddenRequestHeaders: map[string]struct{}{
+			"authorization": {},
+			"cookie":        {},
+			"set-cookie":    {},
+			"x-auth-token":  {},
+			"x-csrf-token":  {},
+			"x-xsrf-token":  {},
+			"user-agent":    {}, // Optional: Include user-agent in hidden headers
+		},
+		redactedQueryParams: map[string]struct{}{
+			"access_token": {},
+			"api_key":      {},
+			"password":     {},
+			"secret":       {},
+			"token":        {},
+		},
+	}
+
+	// Apply each option to the config
+	for _, o := range opts {
+		o.apply(cfg)
+	}
+	return cfg
+}
+
+// init derives the runtime state from the configured options.
+func (cfg *config) init() error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	if err := cfg.initRequests(); err != nil {
+		return err
+	}
+
+	// Keep test suites quiet unless they chose where logs go.
+	if !cfg.outputSet && gin.Mode() == gin.TestMode {
+		cfg.output = io.Discard
+		if cfg.testOutput != nil {
+			cfg.output = cfg.testOutput
+		}
+	}
+
+	if cfg.levelFile != nil {
+		if err := cfg.levelFile.load(); err != nil {
+			return err
+		}
+		cfg.levelFile.next.Store(time.Now().Add(levelFileInterval).UnixNano())
+	}
+
+	if cfg.maxLogsPerSecond > 0 {
+		cfg.logLimiter = newLogLimiter(cfg.maxLogsPerSecond)
+	}
+
+	if cfg.historySize > 0 {
+		cfg.history = newRequestHistory(cfg.historySize)
+	}
+
+	if cfg.notFoundInterval > 0 {
+		cfg.notFound = newNotFoundSummary(cfg.notFoundInterval, cfg.notFoundTopN)
+	}
+
+	if cfg.rotation != nil {
+		f, err := openRotatingFile(*cfg.rotation)
+		if err != nil {
+			return err
+		}
+		cfg.output, cfg.rotating = f, f
+	}
+
+	if cfg.batchRecords > 0 {
+		cfg.batch = NewBatchWriter(cfg.output, cfg.batchRecords, cfg.batchInterval)
+		cfg.output = cfg.batch
+	}
+
+	// Combined-format lines go to the final output, rotated and batched.
+	if cfg.combinedLog {
+		cfg.combined = &combinedWriter{w: cfg.output, redacted: cfg.redactedQueryParams}
+	}
+
+	// Initialize the base logger
+	handler := cfg.handler
+	if cfg.syslog != nil {
+		h, err := cfg.syslog.dial(cfg.defaultLeveler)
+		if err != nil {
+			return err
+		}
+		handler = h
+	}
+	if handler == nil && cfg.legacyFields {
+		handler = slog.NewJSONHandler(cfg.output, cfg.builtinHandlerOptions(legacyReplaceAttr))
+	}
+	if handler == nil && cfg.gcp {
+		handler = slog.NewJSONHandler(cfg.output, cfg.builtinHandlerOptions(GCPReplaceAttr))
+	}
+	if handler == nil && cfg.prettyConsole() {
+		handler = newPrettyHandler(cfg.output, cfg.builtinHandlerOptions(ReplaceLevelNames), prettyColumns{
+			status:  cfg.fieldNames.key(FieldStatus),
+			method:  cfg.fieldNames.key(FieldMethod),
+			latency: cfg.fieldNames.key(FieldLatency),
+			path:    cfg.fieldNames.key(FieldPath),
+		})
+	}
+	if handler == nil {
+		handler = slog.NewTextHandler(cfg.output, cfg.builtinHandlerOptions(ReplaceLevelNames))
+	}
+	cfg.base = cfg.baseLogger
+	if cfg.base == nil {
+		cfg.base = slog.New(handler)
+	}
+	if cfg.dryRun != nil {
+		cfg.base = slog.New(dropHandler{next: cfg.base.Handler()})
+	}
+	if len(cfg.handlerMiddleware) > 0 {
+		h := cfg.base.Handler()
+		for i := len(cfg.handlerMiddleware) - 1; i >= 0; i-- {
+			h = cfg.handlerMiddleware[i](h)
+		}
+		cfg.base = slog.New(h)
+	}
+	if len(cfg.encryptedFields) > 0 {
+		if cfg.keyProvider == nil {
+			return errors.New("encrypted fields require a key provider")
+		}
+		cfg.base = slog.New(&encryptHandler{next: cfg.base.Handler(), fields: cfg.encryptedFields, keys: cfg.keyProvider})
+	}
+	if cfg.redactor != nil {
+		cfg.base = slog.New(&redactHandler{next: cfg.base.Handler(), r: cfg.redactor})
+	}
+	static := cfg.staticAttrs
+	if cfg.hostMetadata {
+		static = append(hostAttrs(), static...)
+	}
+	if len(static) > 0 {
+		cfg.base = slog.New(cfg.base.Handler().WithAttrs(static))
+	}
+	if cfg.routeLoggers != nil {
+		cfg.routeLoggers.bind(cfg.base, cfg.fieldNames)
+	}
+	return cfg.routeOverrides.init(cfg)
+}
+
+// initRequests derives the state used to handle requests, the part of init
+// route overrides redo on their copy of the configuration.
+func (cfg *config) initRequests() error {
+	var base slog.Leveler = cfg.defaultLevel
+	if cfg.levelVar != nil {
+		base = cfg.levelVar
+	}
+	cfg.clientErrorLeveler = cfg.clientErrorLevel
+	cfg.serverErrorLeveler = cfg.serverErrorLevel
+	if cfg.levels != nil {
+		base = cfg.levels.Default
+		cfg.clientErrorLeveler = cfg.levels.ClientError
+		cfg.serverErrorLeveler = cfg.levels.ServerError
+	}
+	cfg.defaultLeveler = base
+	if cfg.startupDuration > 0 {
+		cfg.defaultLeveler = startupLeveler{
+			base:    base,
+			startup: cfg.startupLevel,
+			until:   time.Now().Add(cfg.startupDuration),
+		}
+	}
+
+	cidrLevels, err := parseCIDRLevels(cfg.levelByCIDR)
+	if err != nil {
+		return err
+	}
+	cfg.cidrLevels = cidrLevels
+
+	if cfg.trustedProxies != nil {
+		cfg.trustedPrefixes, err = parsePrefixes(cfg.trustedProxies)
+		if err != nil {
+			return err
+		}
+	}
+
+	// Create a set of paths to skip logging
+	cfg.skipSet = map[string]struct{}{}
+	for _, route := range cfg.skipPath {
+		cfg.skipSet[route] = struct{}{}
+	}
+
+	cfg.bucketLabels = latencyBucketLabels(cfg.latencyBuckets)
+	cfg.hiddenHeaders = newHeaderSet(cfg.hiddenRequestHeaders)
+
+	if cfg.ipHashSalt != nil && len(cfg.ipHashSalt) == 0 {
+		return errors.New("hashed ip requires a salt")
+	}
+
+	if cfg.audit != nil && (cfg.audit.handler == nil || cfg.audit.selector == nil) {
+		return errors.New("audit logger requires a handler and a selector")
+	}
+
+	if cfg.levelHeader != nil && cfg.levelHeader.allowed == nil {
+		return errors.New("level header " + cfg.levelHeader.name + " requires a predicate")
+	}
+
+	if cfg.forceLog != nil {
+		if err := cfg.forceLog.init(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 /*
 SetLogger returns a gin.HandlerFunc (middleware) that logs requests using slog.
 It accepts a variadic number of Option functions to customize the logger's behavior.
@@ -63,6 +510,8 @@ The logger configuration includes:
   - clientErrorLevel: the logging level for client errors (default: slog.LevelWarn).
   - serverErrorLevel: the logging level for server errors (default: slog.LevelError).
   - output: the output writer for the logger (default: gin.DefaultWriter).
+  - handler: a custom slog.Handler to use instead of a text handler writing to output.
+  - baseLogger: an existing *slog.Logger to use as the base logger instead of handler.
   - skipPath: a list of paths to skip logging.
   - skipPathRegexps: a list of regular expressions to skip logging for matching paths.
   - logger: a custom logger function to use instead of the default logger.
@@ -80,116 +529,750 @@ The logging level for each request is determined based on the response status co
   - clientErrorLevel for 4xx status codes.
   - serverErrorLevel for 5xx status codes.
   - defaultLevel for other status codes.
-  - Custom levels can be set for specific paths using the pathLevels configuration.
+  - Custom levels can be set for client networks using the levelByCIDR configuration.
+  - Custom levels can be set for specific paths using the pathLevels configuration,
+    or for route templates such as /users/:id using the routeLevels configuration.
+  - Custom levels can be set for HTTP methods using the methodLevels configuration.
+
+SetLogger panics if the configuration is invalid, e.g. contains a malformed CIDR.
 */
 func SetLogger(opts ...Option) gin.HandlerFunc {
-	cfg := &config{
-		defaultLevel:      slog.LevelInfo,
-		clientErrorLevel:  slog.LevelWarn,
-		serverErrorLevel:  slog.LevelError,
-		output:            os.Stderr,
-		message:           "Request",
-		withRequestHeader: false, // Recommended: enable only in debug/testing, keep disabled by default in production
-		hiddenRequestHeaders: map[string]struct{}{
-			"authorization": {},
-			"cookie":        {},
-			"set-cookie":    {},
-			"x-auth-token":  {},
-			"x-csrf-token":  {},
-			"x-xsrf-token":  {},
-			"user-agent":    {}, // Optional: Include user-agent in hidden headers
-		},
+	cfg := newConfig(opts...)
+	if err := cfg.init(); err != nil {
+		panic("slog: " + err.Error())
 	}
+	return cfg.handle
+}
 
-	// Apply each option to the config
-	for _, o := range opts {
-		o.apply(cfg)
+// handle logs a single request.
+func (cfg *config) handle(c *gin.Context) {
+	if c.Request == nil || c.Request.URL == nil {
+		cfg.handleBare(c)
+		return
 	}
 
-	// Create a set of paths to skip logging
-	skip := map[string]struct{}{}
-	for _, route := range cfg.skipPath {
-		skip[route] = struct{}{}
+	if cfg.nestedPolicy == NestedLogOutermost && nestedDepth(c) > 0 {
+		c.Next()
+		return
 	}
+	if oc := cfg.routeOverrides.match(c.FullPath()); oc != nil {
+		cfg = oc
+	}
+	depth := enterNested(c)
+	cfg.dryRun.add(statRequests)
 
-	// Initialize the base logger
-	handler := slog.NewTextHandler(cfg.output, &slog.HandlerOptions{
-		Level: cfg.defaultLevel,
-	})
-	l := slog.New(handler)
+	r := &request{start: time.Now(), route: cfg.route(c)}
+	cfg.requestLogger(c, r)
+
+	if (cfg.debugRequests != nil && cfg.debugRequests.take(debugRequestID(c))) ||
+		(cfg.levelHeader != nil && cfg.levelHeader.requested(c)) {
+		cfg = cfg.debugConfig()
+		r.debug = true
+		// The handler's own level would drop the debug records.
+		r.logger = slog.New(debugHandler{next: r.logger.Handler()})
+	}
+
+	r.force = r.debug || (cfg.forceLog != nil && cfg.forceLog.forced(cfg, c))
+
+	r.path = c.Request.URL.Path
+	r.query = c.Request.URL.RawQuery
+	if cfg.dryRun == nil {
+		c.Set(loggerKey, r.logger)
+		c.Set(configKey, cfg)
+		c.Request = c.Request.WithContext(NewContext(c.Request.Context(), r.logger))
+	}
 
-	return func(c *gin.Context) {
-		rl := l
-		if cfg.logger != nil {
-			rl = cfg.logger(c, l)
+	if cfg.graphqlPath != "" && r.path == cfg.graphqlPath {
+		if op, ok := peekGraphQL(c); ok {
+			r.graphql = &op
 		}
+	}
 
-		start := time.Now()
-		route := c.Request.URL.Path
-		query := c.Request.URL.RawQuery
-		c.Set(loggerKey, rl)
+	if cfg.costSummary {
+		r.cost = &cost{cpu: cpuSeconds()}
+		if c.Request.Body != nil && c.Request.Body != http.NoBody {
+			r.cost.body = &bodyCounter{ReadCloser: c.Request.Body}
+			c.Request.Body = r.cost.body
+		}
+	}
 
-		c.Next()
+	if cfg.requestBodyMax > 0 && c.Request.Body != nil && c.Request.Body != http.NoBody &&
+		mediaTypeAllowed(c.ContentType(), cfg.requestBodyTypes) {
+		r.reqBody = newBodyCapture(c.Request.Body, cfg.requestBodyMax)
+		c.Request.Body = r.reqBody
+	}
+
+	if cfg.bodyDigest != "" && c.Request.Body != nil && c.Request.Body != http.NoBody {
+		r.reqDigest = &digestReader{ReadCloser: c.Request.Body, bodyDigest: bodyDigest{alg: cfg.bodyDigest, h: cfg.bodyDigest.new()}}
+		c.Request.Body = r.reqDigest
+	}
+
+	if cfg.multipartMax > 0 && c.Request.Body != nil && c.Request.Body != http.NoBody {
+		if r.multipart = newMultipartTee(c.Request.Body, c.GetHeader("Content-Type"), cfg.multipartMax); r.multipart != nil {
+			c.Request.Body = r.multipart
+			defer r.multipart.finish()
+		}
+	}
+
+	r.hw.ResponseWriter = c.Writer
+	c.Writer = &r.hw
+	if cfg.abortDetection {
+		r.abort = &abortWriter{ResponseWriter: c.Writer}
+		c.Writer = r.abort
+	}
+	if cfg.streamingMetrics {
+		r.stream = &streamWriter{ResponseWriter: c.Writer}
+		c.Writer = r.stream
+	}
+	if cfg.latencyBreakdown {
+		r.timer = &writeTimer{ResponseWriter: c.Writer}
+		c.Writer = r.timer
+	}
+
+	if cfg.startLog {
+		cfg.logStart(c, r)
+	}
 
-		skipRoute := route
-		if query != "" {
-			skipRoute += "?" + query
+	if cfg.responseBodyMax > 0 {
+		r.rw = &responseWriter{
+			ResponseWriter: c.Writer,
+			bodyMax:        cfg.responseBodyMax,
+			successBody:    r.debug,
 		}
+		c.Writer = r.rw
+	}
+	if cfg.bodyDigest != "" && cfg.responseDigest {
+		r.respDigest = &digestWriter{ResponseWriter: c.Writer, bodyDigest: bodyDigest{alg: cfg.bodyDigest, h: cfg.bodyDigest.new()}}
+		c.Writer = r.respDigest
+	}
+	if cfg.errorIDHeader != "" && cfg.dryRun == nil {
+		r.errorID = &errorIDWriter{ResponseWriter: c.Writer, header: cfg.errorIDHeader}
+		c.Writer = r.errorID
+		c.Set(errorIDKey, r.errorID)
+	}
+
+	if cfg.errorWriter != nil {
+		r.errorOutput = captureErrors(c, func() {
+			r.panic = runHandlers(c, cfg.recovery)
+		})
+	} else {
+		r.panic = runHandlers(c, cfg.recovery)
+	}
+	if r.errorID != nil {
+		// A 5xx status set without a body is written by gin after the middleware returns.
+		r.errorID.check()
+	}
+
+	if cfg.audit != nil && cfg.dryRun == nil {
+		cfg.audit.log(cfg, c, r)
+	}
+
+	// A dry run next to the middleware in use would count its requests twice.
+	if cfg.metrics != nil && cfg.dryRun == nil {
+		cfg.metrics.ObserveRequest(r.route, c.Request.Method, c.Writer.Status(), c.Writer.Size(), time.Since(r.start))
+	}
+
+	if cfg.nestedPolicy == NestedLogInnermost && nestedDepth(c) > depth {
+		cfg.dryRun.add(statSkipped)
+		return
+	}
 
-		track := !shouldSkipLogging(skipRoute, skip, cfg, c)
-		if !track {
+	if !r.force {
+		if shouldSkipLogging(r.skipRoute(), cfg.skipSet, cfg, c) {
+			cfg.dryRun.add(statSkipped)
 			return
 		}
 
-		end := time.Now()
-		if cfg.utc {
-			end = end.UTC()
+		if cfg.notFound != nil && c.FullPath() == "" && c.Writer.Status() == http.StatusNotFound {
+			cfg.notFound.add(cfg.base, cfg.clientErrorLeveler.Level(), r.path, time.Now())
+			cfg.dryRun.add(statSkipped)
+			return
 		}
 
-		msg := cfg.message
-		if len(c.Errors) > 0 {
-			msg += " with errors: " + c.Errors.String()
+		if !shouldSample(cfg, c, r.route, r.path) {
+			cfg.dryRun.add(statSampledOut)
+			if len(cfg.observers) == 0 || cfg.dryRun != nil {
+				return
+			}
+			r.sampledOut = true
 		}
+	}
 
-		latency := time.Since(start)
-		status := c.Writer.Status()
-		method := c.Request.Method
-		userAgent := c.Request.UserAgent()
-		ip := c.ClientIP()
-		referer := c.Request.Referer()
+	cfg.measure(c, r)
+	if cfg.errorRate != nil && !r.sampledOut && cfg.dryRun == nil {
+		cfg.errorRate.observe(c.Writer.Status(), r.end)
+	}
+	if !r.force && ((cfg.errorsOnly && !r.escalated(c)) || r.latency < cfg.minLatency ||
+		(cfg.filters != nil && !cfg.filters(c, r.latency))) {
+		cfg.dryRun.add(statFiltered)
+		return
+	}
+	// Skip building a record the handler would discard, unless hooks or the
+	// combined log format need it whatever the handler's level.
+	if !r.debug && cfg.context == nil && cfg.combined == nil &&
+		len(cfg.accessHooks) == 0 && len(cfg.errorHooks) == 0 && len(cfg.reporters) == 0 && len(cfg.observers) == 0 &&
+		!r.logger.Handler().Enabled(c.Request.Context(), cfg.mapLevel(r.level)) {
+		cfg.dryRun.add(statBelowLevel)
+		return
+	}
+	if cfg.logLimiter != nil && !r.force && !r.sampledOut &&
+		!cfg.logLimiter.allow(cfg.base, slog.LevelWarn, r.end) {
+		cfg.dryRun.add(statRateLimited)
+		return
+	}
 
-		level := getLogLevel(cfg, c, route)
-		record := slog.NewRecord(end, level, msg, 0)
-		record.Add("status", status)
-		record.Add("method", method)
-		record.Add("path", route)
-		record.Add("query", query)
-		record.Add("route", c.FullPath())
-		record.Add("ip", ip)
-		record.Add("latency", latency)
-		record.Add("referer", referer)
-		record.Add("user_agent", userAgent)
-		record.Add("body_size", c.Writer.Size())
+	record, access := cfg.newRecord(c, r)
+	recPtr := &record
+	if cfg.context != nil {
+		recPtr = cfg.context(c, recPtr)
+	}
 
-		// Add visible HTTP request headers as a log field if enabled
-		if cfg.withRequestHeader && c.Request.Header != nil {
-			headers := extractVisibleHeaders(c.Request.Header, cfg.hiddenRequestHeaders)
-			record.Add("headers", headers)
+	recPtr.Level = cfg.mapLevel(recPtr.Level)
+	access.Level = recPtr.Level
+	if r.sampledOut {
+		recPtr.AddAttrs(slog.Attr{Key: cfg.fieldNames.key(FieldSampledOut), Value: slog.BoolValue(true)})
+		for _, fn := range cfg.observers {
+			fn(c, recPtr.Clone())
 		}
+		return
+	}
+	if cfg.dryRun != nil {
+		cfg.dryRunRecord(c, r, *recPtr)
+		return
+	}
+	if cfg.combined != nil {
+		cfg.combined.write(c, access, r.start)
+		cfg.runHooks(c, access, *recPtr)
+		return
+	}
 
-		recPtr := &record
-		if cfg.context != nil {
-			recPtr = cfg.context(c, recPtr)
+	ctx := c.Request.Context()
+	if cfg.detachContext {
+		ctx = context.WithoutCancel(ctx)
+		if cfg.detachTimeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.detachTimeout)
+			defer cancel()
 		}
+	}
+	ctx = contextWithAccessRecord(ctx, access)
+	logged := dedupeRecord(*recPtr, cfg.conflictPolicy)
+	_ = r.logger.Handler().Handle(ctx, logged)
+	cfg.runHooks(c, access, logged)
+}
 
-		_ = rl.Handler().Handle(c.Request.Context(), *recPtr)
+// dryRunRecord counts a record a dry run would write, passing it through the
+// handler chain, which discards it, so redaction and encryption are exercised.
+func (cfg *config) dryRunRecord(c *gin.Context, r *request, record slog.Record) {
+	h := r.logger.Handler()
+	if cfg.combined == nil && !h.Enabled(c.Request.Context(), record.Level) {
+		cfg.dryRun.add(statBelowLevel)
+		return
 	}
+	cfg.dryRun.logged(record.Level)
+	_ = h.Handle(c.Request.Context(), dedupeRecord(record, cfg.conflictPolicy))
+}
+
+// runHooks passes a logged request to the access record and error hooks, the
+// observers and the reporters, redacting the record for all but the access hooks.
+func (cfg *config) runHooks(c *gin.Context, access *AccessRecord, record slog.Record) {
+	if cfg.redactor != nil {
+		record = cfg.redactor.record(record)
+	}
+	if cfg.history != nil {
+		cfg.addHistory(access, record)
+	}
+	for _, hook := range cfg.accessHooks {
+		hook(c, access)
+	}
+	if record.Level >= cfg.serverErrorLeveler.Level() {
+		for _, hook := range cfg.errorHooks {
+			hook(c, record.Clone())
+		}
+	}
+	for _, fn := range cfg.observers {
+		fn(c, record.Clone())
+	}
+	var attrs []slog.Attr
+	for _, rep := range cfg.reporters {
+		if record.Level < rep.level {
+			continue
+		}
+		if attrs == nil {
+			attrs = make([]slog.Attr, 0, record.NumAttrs())
+			record.Attrs(func(a slog.Attr) bool {
+				attrs = append(attrs, a)
+				return true
+			})
+		}
+		rep.fn(c, record.Level, record.Message, attrs)
+	}
+}
+
+// handleBare serves requests without an *http.Request or URL, as built by some
+// tests and internal dispatchers, logging a minimal record unless disabled.
+func (cfg *config) handleBare(c *gin.Context) {
+	start := time.Now()
+	c.Set(loggerKey, cfg.base)
+	p := runHandlers(c, cfg.recovery)
+	if cfg.skipBareRequests {
+		return
+	}
+
+	end := time.Now()
+	if cfg.utc {
+		end = end.UTC()
+	}
+	status := c.Writer.Status()
+	msg := cfg.message
+	if m, ok := cfg.messages[statusClassOf(status)]; ok {
+		msg = m
+	}
+	b := NewRecordBuilder(end, getLogLevel(cfg, c, "", ""), msg)
+	b.names = cfg.fieldNames
+	b.group = cfg.group
+	b.Add(FieldStatus, status).
+		add(FieldLatency, cfg.latencyFormat.value(time.Since(start))).
+		Add(FieldBodySize, c.Writer.Size())
+	if p != nil {
+		b.Add(FieldPanic, cfg.panicFormatter(p.value))
+		b.Add(FieldStack, string(p.stack))
+	}
+	_ = cfg.base.Handler().Handle(context.Background(), b.Record())
+}
+
+// requestLogger derives the request-scoped logger, along with the request's
+// trace ids. r.pooled reports whether the logger already carries the method
+// and route attributes.
+func (cfg *config) requestLogger(c *gin.Context, r *request) {
+	rl := cfg.base
+	if cfg.routeLoggers != nil {
+		rl, r.pooled = cfg.routeLoggers.get(c.Request.Method, r.route)
+	}
+	if cfg.keyedLoggers != nil {
+		rl = cfg.keyedLoggers.get(c, rl)
+	}
+	var attrs []any
+	traced := false
+	if cfg.withTraceID {
+		r.traceID, r.spanID, traced = spanIDs(c.Request.Context())
+	}
+	if th, ok := parseTraceHeaders(c, cfg.traceFormats); ok {
+		// A dry run leaves the response to the middleware in use.
+		if cfg.dryRun == nil {
+			for k, v := range th.headers {
+				c.Header(k, v)
+			}
+		}
+		if !traced {
+			r.traceID, r.spanID, traced = th.traceID, th.spanID, true
+		}
+	}
+	if traced {
+		attrs = traceAttrs(cfg.fieldNames, r.traceID, r.spanID)
+	}
+	if cfg.requestIDHeader != "" {
+		if cfg.dryRun != nil {
+			r.requestID = requestID(c, cfg.requestIDHeader, cfg.requestIDGenerator)
+		} else {
+			r.requestID = setRequestID(c, cfg.requestIDHeader, cfg.requestIDGenerator)
+		}
+		attrs = append(attrs, cfg.fieldNames.key(FieldRequestID), r.requestID)
+	}
+	if cfg.tenant != nil {
+		if r.tenant = cfg.tenant(c); r.tenant != "" {
+			attrs = append(attrs, cfg.fieldNames.key(FieldTenant), r.tenant)
+		}
+	}
+	if cfg.baggage {
+		if a, ok := cfg.baggageAttr(c); ok {
+			attrs = append(attrs, a)
+		}
+	}
+	if attrs != nil {
+		rl = rl.With(attrs...)
+	}
+	if cfg.logger != nil {
+		rl = cfg.logger(c, rl)
+	}
+	r.logger = rl
+}
+
+// route returns the route attribute of the request: the normalized route when
+// WithRouteNormalizer is set, the matched route template otherwise.
+func (cfg *config) route(c *gin.Context) string {
+	if cfg.routeNormalizer != nil {
+		return cfg.routeNormalizer(c)
+	}
+	return c.FullPath()
+}
+
+// escalated reports whether a measured request is worth logging with
+// WithErrorsOnly: it failed, panicked, or was slow, large or aborted.
+func (r *request) escalated(c *gin.Context) bool {
+	return c.Writer.Status() >= http.StatusBadRequest || r.panic != nil || r.slow || r.large || r.aborted
+}
+
+// measure records the end time, latency, client IP and level of a handled request.
+func (cfg *config) measure(c *gin.Context, r *request) {
+	r.end = time.Now()
+	if cfg.utc {
+		r.end = r.end.UTC()
+	}
+	r.latency = r.end.Sub(r.start)
+	r.ip = clientIP(cfg, c)
+	r.level = getLogLevel(cfg, c, r.path, r.ip)
+	if _, ok := cfg.healthPaths[r.path]; ok && c.Writer.Status() >= http.StatusBadRequest {
+		r.level = slog.LevelWarn
+	}
+	if r.abort != nil && clientAborted(c, r.abort) {
+		r.aborted = true
+		r.level = cfg.abortLevel
+	}
+	r.slow = cfg.slowThreshold > 0 && r.latency > cfg.slowThreshold
+	if r.slow && r.level < cfg.slowLevel {
+		r.level = cfg.slowLevel
+	}
+	r.large = cfg.largeResponse > 0 && int64(c.Writer.Size()) > cfg.largeResponse
+	if r.large && r.level < cfg.largeResponseLevel {
+		r.level = cfg.largeResponseLevel
+	}
+}
+
+// skipRoute returns the path and query matched against skip paths.
+func (r *request) skipRoute() string {
+	if r.query != "" {
+		return r.path + "?" + r.query
+	}
+	return r.path
+}
+
+// logStart logs the start record of a request, unless its path is skipped.
+func (cfg *config) logStart(c *gin.Context, r *request) {
+	if !r.force {
+		route := r.skipRoute()
+		if _, ok := cfg.skipSet[route]; ok || skipPathRegexp(cfg, route) {
+			return
+		}
+	}
+	ctx := c.Request.Context()
+	level := cfg.mapLevel(cfg.startLevel)
+	h := r.logger.Handler()
+	if !h.Enabled(ctx, level) {
+		return
+	}
+	t := r.start
+	if cfg.utc {
+		t = t.UTC()
+	}
+	b := NewRecordBuilder(t, level, "Request started")
+	b.fields = cfg.fields
+	b.names = cfg.fieldNames
+	b.group = cfg.group
+	if !r.pooled {
+		b.add(FieldMethod, slog.StringValue(c.Request.Method))
+	}
+	b.add(FieldPath, slog.StringValue(r.path))
+	b.add(FieldIP, slog.StringValue(cfg.loggedIP(clientIP(cfg, c))))
+	_ = h.Handle(ctx, b.Record())
+}
+
+// builtinHandlerOptions returns the options of the built-in handler: the
+// WithHandlerOptions ones, with the default level unless they set one, and
+// their ReplaceAttr applied after replace and the time settings.
+func (cfg *config) builtinHandlerOptions(replace func([]string, slog.Attr) slog.Attr) *slog.HandlerOptions {
+	opts := slog.HandlerOptions{}
+	if cfg.handlerOptions != nil {
+		opts = *cfg.handlerOptions
+	}
+	if opts.Level == nil {
+		opts.Level = cfg.defaultLeveler
+	}
+	replace = cfg.replaceTime(replace)
+	if user := opts.ReplaceAttr; user != nil {
+		opts.ReplaceAttr = func(groups []string, a slog.Attr) slog.Attr {
+			return user(groups, replace(groups, a))
+		}
+	} else {
+		opts.ReplaceAttr = replace
+	}
+	return &opts
+}
+
+// replaceTime applies the WithTimeFormat and WithTimeKey settings, if any, after next.
+func (cfg *config) replaceTime(next func([]string, slog.Attr) slog.Attr) func([]string, slog.Attr) slog.Attr {
+	if cfg.timeFormat == "" && cfg.timeKey == "" {
+		return next
+	}
+	return replaceTime(cfg.timeFormat, cfg.timeKey, next)
+}
+
+// mapLevel applies the WithLevelMapper function, if any.
+func (cfg *config) mapLevel(l slog.Level) slog.Level {
+	if cfg.levelMapper != nil {
+		return cfg.levelMapper(l)
+	}
+	return l
+}
+
+// newRecord builds the access log record of a request measured by measure,
+// along with its typed AccessRecord.
+func (cfg *config) newRecord(c *gin.Context, r *request) (slog.Record, *AccessRecord) {
+	end := r.end
+	status := c.Writer.Status()
+	msg := cfg.message
+	if m, ok := cfg.messages[statusClassOf(status)]; ok {
+		msg = m
+	}
+	meta, hasMeta := cfg.routeMeta[c.FullPath()]
+	if hasMeta && meta.message != "" {
+		msg = meta.message
+	}
+	errs := c.Errors.ByType(cfg.errorTypes)
+	if len(errs) > 0 && cfg.errorsInMessage {
+		msg += " with errors: " + errs.String()
+	}
+
+	ip := cfg.loggedIP(r.ip)
+	rec := &r.access
+	*rec = AccessRecord{
+		Time:      end,
+		Level:     r.level,
+		Message:   msg,
+		Status:    status,
+		Method:    c.Request.Method,
+		Path:      r.path,
+		Query:     r.query,
+		Route:     r.route,
+		IP:        ip,
+		Latency:   r.latency,
+		Slow:      r.slow,
+		Large:     r.large,
+		Referer:   c.Request.Referer(),
+		UserAgent: c.Request.UserAgent(),
+		BodySize:  c.Writer.Size(),
+		RequestID: cmp.Or(r.requestID, RequestID(c)),
+		Tenant:    r.tenant,
+		TraceID:   r.traceID,
+		SpanID:    r.spanID,
+		Errors:    errs.Errors(),
+	}
+	b := NewRecordBuilder(end, rec.Level, msg)
+	b.fields = cfg.fields
+	if cfg.layout != nil {
+		b.fields = layoutFields
+	}
+	b.names = cfg.fieldNames
+	b.group = cfg.group
+	b.add(FieldStatus, slog.IntValue(status))
+	if !r.pooled {
+		b.add(FieldMethod, slog.StringValue(rec.Method))
+	}
+	b.add(FieldPath, slog.StringValue(r.path))
+	b.add(FieldQuery, slog.StringValue(r.query))
+	if cfg.withURI {
+		rec.URI = requestURI(c.Request.URL, cfg.redactedQueryParams)
+		b.add(FieldURI, slog.StringValue(rec.URI))
+	}
+	if cfg.connInfo {
+		rec.Proto, rec.Host, rec.Scheme = c.Request.Proto, c.Request.Host, requestScheme(c.Request)
+		b.Add(FieldProto, rec.Proto).Add(FieldHost, rec.Host).Add(FieldScheme, rec.Scheme)
+	}
+	if !r.pooled {
+		b.add(FieldRoute, slog.StringValue(rec.Route))
+	}
+	b.add(FieldIP, slog.StringValue(ip))
+	if cfg.forwardedChain {
+		chain := ForwardedChain(c.Request)
+		chain = chain[cfg.trustedFrom(c, len(chain), func(i int) string { return chain[i] }):]
+		if rec.ForwardedFor = chain; len(rec.ForwardedFor) > 0 {
+			for i, hop := range rec.ForwardedFor {
+				rec.ForwardedFor[i] = cfg.loggedIP(hop)
+			}
+			b.Add(FieldForwardedFor, rec.ForwardedFor)
+		}
+	}
+	if cfg.forwarded {
+		hops := ProxyHops(c.Request)
+		hops = hops[cfg.trustedFrom(c, len(hops), func(i int) string { return hops[i].For }):]
+		if len(hops) > 0 {
+			for i := range hops {
+				hops[i].For, hops[i].By = cfg.loggedIP(hops[i].For), cfg.loggedIP(hops[i].By)
+			}
+			rec.Forwarded = &hops[0]
+			b.AddAttrs(hops[0].attr(cfg.fieldNames.key(FieldForwarded)))
+			if cfg.proxyChain {
+				rec.ProxyChain = hops
+				b.Add(FieldProxyChain, hops)
+			}
+		}
+	}
+	if cfg.geoIP != nil {
+		if attrs := geoAttrs(c, cfg.geoIP, r.ip); len(attrs) > 0 {
+			b.AddAttrs(slog.Attr{Key: cfg.fieldNames.key(FieldGeo), Value: slog.GroupValue(attrs...)})
+		}
+	}
+	if r.graphql != nil {
+		rec.GraphQLOp, rec.GraphQLType = r.graphql.name, r.graphql.typ
+		b.Add(FieldGraphQLOperation, rec.GraphQLOp).Add(FieldGraphQLType, rec.GraphQLType)
+	}
+	b.add(FieldLatency, cfg.latencyFormat.value(rec.Latency))
+	if r.timer != nil {
+		b.AddAttrs(cfg.latencyBreakdownAttr(c, r))
+	}
+	if cfg.principal != nil {
+		var extra []slog.Attr
+		if rec.Principal, extra = cfg.principal(c); rec.Principal != "" {
+			b.Add(FieldPrincipal, rec.Principal)
+		}
+		b.AddAttrs(extra...)
+	}
+	if rec.Slow {
+		b.add(FieldSlow, slog.BoolValue(true))
+	}
+	if rec.Large {
+		b.Add(FieldLargeResponse, true)
+	}
+	if len(cfg.bucketLabels) > 0 {
+		rec.LatencyBucket = cfg.bucketLabels[latencyBucket(cfg.latencyBuckets, rec.Latency)]
+		b.add(FieldLatencyBucket, slog.StringValue(rec.LatencyBucket))
+	}
+	b.add(FieldReferer, slog.StringValue(rec.Referer))
+	b.add(FieldUserAgent, slog.StringValue(rec.UserAgent))
+	b.add(FieldBodySize, slog.IntValue(rec.BodySize))
+	if cfg.contentTypes {
+		rec.RequestType = c.Request.Header.Get("Content-Type")
+		rec.ResponseType = c.Writer.Header().Get("Content-Type")
+		b.Add(FieldRequestContentType, rec.RequestType).Add(FieldResponseContentType, rec.ResponseType)
+	}
+	if cfg.requestSize {
+		if rec.RequestSize = c.Request.ContentLength; rec.RequestSize >= 0 {
+			b.Add(FieldRequestSize, rec.RequestSize)
+		}
+		if r.reqBody != nil {
+			rec.RequestRead = r.reqBody.read
+			b.Add(FieldRequestBodyRead, rec.RequestRead)
+		}
+	}
+	if cfg.layout != nil {
+		b.AddAttrs(cfg.layout(rec)...)
+	}
+	if cfg.otelSemConv {
+		b.AddAttrs(otelServerAttrs(c.Request)...)
+	}
+	if r.abort != nil {
+		if err := c.Request.Context().Err(); err != nil {
+			rec.ContextError = err.Error()
+			b.Add(FieldContextError, rec.ContextError)
+		}
+		if rec.ClientAborted = r.aborted; r.aborted {
+			b.Add(FieldClientAborted, true)
+		}
+	}
+	if r.errorID != nil && r.errorID.id != "" {
+		rec.ErrorID = r.errorID.id
+		b.Add(FieldErrorID, rec.ErrorID)
+	}
+	if cfg.deadlineInfo {
+		ctx := c.Request.Context()
+		if deadline, ok := ctx.Deadline(); ok {
+			rec.Budget, rec.BudgetLeft = deadline.Sub(r.start), deadline.Sub(r.end)
+			b.Add(FieldDeadlineBudget, rec.Budget).Add(FieldDeadlineRemaining, rec.BudgetLeft)
+		}
+		if rec.TimedOut = errors.Is(ctx.Err(), context.DeadlineExceeded); rec.TimedOut {
+			b.Add(FieldTimedOut, true)
+		}
+	}
+	if r.stream != nil && !r.stream.first.IsZero() {
+		rec.TTFB, rec.Stream = r.stream.first.Sub(r.start), r.end.Sub(r.stream.first)
+		b.Add(FieldTTFB, rec.TTFB).Add(FieldStreamDuration, rec.Stream)
+	}
+	if r.hw.hijacked.Load() {
+		rec.Hijacked = true
+		b.Add(FieldHijacked, true)
+	}
+
+	if r.cost != nil {
+		b.AddAttrs(r.cost.attr(cfg.fieldNames.key(FieldCost), rec.BodySize, rec.Latency))
+	}
+
+	if r.panic != nil {
+		v := cfg.panicFormatter(r.panic.value)
+		rec.Panic = &v
+		b.Add(FieldPanic, v)
+		b.Add(FieldStack, string(r.panic.stack))
+	}
+	if len(r.errorOutput) > 0 {
+		b.Add(FieldErrorOutput, r.errorOutput)
+	}
+
+	// Add visible HTTP request headers as a log field if enabled
+	if cfg.withRequestHeader && c.Request.Header != nil {
+		headers := extractVisibleHeaders(c.Request.Header, cfg.hiddenHeaders, cfg.headerMaskPrefix)
+		b.add(FieldHeaders, slog.GroupValue(headers...))
+	}
+	if len(cfg.headerAttrs) > 0 {
+		b.AddAttrs(cfg.promotedHeaders(c)...)
+	}
+
+	if r.reqBody != nil {
+		body := r.reqBody.buf.String()
+		rec.RequestBody = &body
+		b.Add(FieldRequestBody, body)
+		if r.reqBody.truncated() {
+			b.Add(FieldRequestBodyTruncated, true)
+		}
+	}
+
+	if r.reqDigest != nil {
+		b.AddAttrs(r.reqDigest.attrs(cfg.fieldNames.key(FieldRequestBody), cfg.fieldNames.key(FieldRequestBodyBytes))...)
+	}
+
+	if r.multipart != nil {
+		if a, ok := r.multipart.attr(cfg.fieldNames.key(FieldMultipart)); ok {
+			b.AddAttrs(a)
+		}
+	}
+
+	if cfg.curl != nil && status >= cfg.curl.minStatus {
+		b.Add(FieldCurl, cfg.curl.command(cfg, c.Request, r.reqBody))
+	}
+
+	if len(errs) > 0 && !cfg.errorsInMessage {
+		withStack := cfg.errorStackTrace && status >= http.StatusInternalServerError
+		b.AddAttrs(errorsAttr(cfg.fieldNames.key(FieldErrors), errs, withStack))
+	}
+	if cfg.errorTypeAttrs {
+		b.AddAttrs(errorTypeAttrs(errs, cfg.fieldNames.key(FieldErrorCounts))...)
+	}
+
+	if r.rw != nil && (r.debug || status >= http.StatusBadRequest) {
+		body := r.rw.body.String()
+		rec.ResponseBody = &body
+		b.Add(FieldResponseBody, body)
+	}
+	if r.respDigest != nil {
+		b.AddAttrs(r.respDigest.attrs(cfg.fieldNames.key(FieldResponseBody), cfg.fieldNames.key(FieldResponseBodyBytes))...)
+	}
+
+	if r.debug {
+		b.Add(FieldDebugCapture, true)
+	}
+
+	if hasMeta {
+		b.AddAttrs(meta.attrs...)
+	}
+	if len(cfg.contextKeys) > 0 {
+		b.AddAttrs(contextKeyAttrs(c, cfg.contextKeys)...)
+	}
+	b.AddAttrs(requestAttrs(c)...)
+	return b.Record(), rec
 }
 
 /*
 ParseLevel parses a string representation of a log level and returns the corresponding slog.Level.
 It takes a single argument:
-  - levelStr: a string representing the log level (e.g., "debug", "info", "warn", "error").
+  - levelStr: a string representing the log level (e.g., "trace", "debug", "info", "warn", "error", "fatal").
 
 It returns:
   - slog.Level: the parsed log level.
@@ -197,6 +1280,8 @@ It returns:
 */
 func ParseLevel(levelStr string) (slog.Level, error) {
 	switch levelStr {
+	case "trace":
+		return LevelTrace, nil
 	case "debug":
 		return slog.LevelDebug, nil
 	case "info":
@@ -205,26 +1290,120 @@ func ParseLevel(levelStr string) (slog.Level, error) {
 		return slog.LevelWarn, nil
 	case "error":
 		return slog.LevelError, nil
+	case "fatal":
+		return LevelFatal, nil
 	default:
 		return slog.LevelInfo, fmt.Errorf("unknown slog level: %s", levelStr)
 	}
 }
 
-// extractVisibleHeaders filters HTTP headers by hidden list.
-func extractVisibleHeaders(header http.Header, hidden map[string]struct{}) map[string]any {
-	filtered := make(map[string]any, len(header))
+// extractVisibleHeaders filters HTTP headers by hidden list, returning them as
+// group attributes. Hidden headers are dropped, or masked keeping maskPrefix
+// characters when maskPrefix >= 0.
+func extractVisibleHeaders(header http.Header, hidden headerSet, maskPrefix int) []slog.Attr {
+	filtered := make([]slog.Attr, 0, len(header))
 	for k, v := range header {
-		if _, exists := hidden[strings.ToLower(k)]; !exists {
-			filtered[k] = v
+		if !hidden.contains(k) {
+			filtered = append(filtered, slog.Any(k, v))
+		} else if maskPrefix >= 0 {
+			masked := make([]string, len(v))
+			for i, s := range v {
+				masked[i] = maskHeaderValue(s, maskPrefix)
+			}
+			filtered = append(filtered, slog.Any(k, masked))
 		}
 	}
 	return filtered
 }
 
+// headerAttr maps a header to the attribute key it is logged as.
+type headerAttr struct {
+	header, key string
+}
+
+// promotedHeaders returns the WithHeaderAttrs attributes: the values of the
+// request headers, or else of the response headers, joined with ", ". Hidden
+// headers are dropped, or masked like in the headers group.
+func (cfg *config) promotedHeaders(c *gin.Context) []slog.Attr {
+	var attrs []slog.Attr
+	for _, h := range cfg.headerAttrs {
+		values := c.Request.Header.Values(h.header)
+		if len(values) == 0 {
+			values = c.Writer.Header().Values(h.header)
+		}
+		if len(values) == 0 {
+			continue
+		}
+		v := strings.Join(values, ", ")
+		if cfg.hiddenHeaders.contains(h.header) {
+			if cfg.headerMaskPrefix < 0 {
+				continue
+			}
+			v = maskHeaderValue(v, cfg.headerMaskPrefix)
+		}
+		attrs = append(attrs, slog.String(h.key, v))
+	}
+	return attrs
+}
+
+// headerSet is a case-insensitive set of header names.
+type headerSet struct {
+	lower     map[string]struct{} // lower-case names
+	canonical map[string]struct{} // canonical names, for allocation-free lookups
+}
+
+func newHeaderSet(lower map[string]struct{}) headerSet {
+	canonical := make(map[string]struct{}, len(lower))
+	for h := range lower {
+		canonical[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	return headerSet{lower: lower, canonical: canonical}
+}
+
+// contains reports whether the set holds the name. Canonical names, which
+// servers receive, are looked up without lower-casing them.
+func (s headerSet) contains(name string) bool {
+	if _, ok := s.canonical[name]; ok {
+		return true
+	}
+	if http.CanonicalHeaderKey(name) == name {
+		return false
+	}
+	_, ok := s.lower[strings.ToLower(name)]
+	return ok
+}
+
+// maskHeaderValue keeps the auth scheme (e.g. "Bearer ") and the first prefix
+// bytes of the credential, masking the rest. Credentials no longer than twice
+// the prefix are fully masked so short secrets are not mostly revealed.
+func maskHeaderValue(v string, prefix int) string {
+	scheme := ""
+	if i := strings.IndexByte(v, ' '); i >= 0 {
+		scheme, v = v[:i+1], v[i+1:]
+	}
+	if len(v) <= 2*prefix {
+		prefix = 0
+	}
+	return scheme + v[:prefix] + "****"
+}
+
 func shouldSkipLogging(route string, skip map[string]struct{}, cfg *config, c *gin.Context) bool {
 	if _, ok := skip[route]; ok || (cfg.skip != nil && cfg.skip(c)) {
 		return true
 	}
+	if skipPathRegexp(cfg, route) {
+		return true
+	}
+	for _, s := range cfg.skippers {
+		if s(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// skipPathRegexp reports whether route matches a WithSkipPathRegexps rule.
+func skipPathRegexp(cfg *config, route string) bool {
 	for _, reg := range cfg.skipPathRegexps {
 		if reg.MatchString(route) {
 			return true
@@ -233,21 +1412,56 @@ func shouldSkipLogging(route string, skip map[string]struct{}, cfg *config, c *g
 	return false
 }
 
-func getLogLevel(cfg *config, c *gin.Context, route string) slog.Level {
+func statusClassOf(status int) StatusClass {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return StatusClassServerError
+	case status >= http.StatusBadRequest:
+		return StatusClassClientError
+	default:
+		return StatusClassSuccess
+	}
+}
+
+func getLogLevel(cfg *config, c *gin.Context, route, ip string) slog.Level {
+	fl := cfg.levelFile.current(time.Now(), cfg.base)
+	if lvl, has := fl.status[c.Writer.Status()]; has {
+		return lvl
+	}
 	if lvl, has := cfg.specificLevelByStatusCode[c.Writer.Status()]; has {
 		return lvl
 	}
 	if c.Writer.Status() >= http.StatusBadRequest &&
 		c.Writer.Status() < http.StatusInternalServerError {
-		return cfg.clientErrorLevel
+		return cfg.clientErrorLeveler.Level()
 	}
 	if c.Writer.Status() >= http.StatusInternalServerError {
-		return cfg.serverErrorLevel
+		return cfg.serverErrorLeveler.Level()
+	}
+	if cfg.redirectLevel != nil && c.Writer.Status() >= http.StatusMultipleChoices {
+		return cfg.redirectLevel.Level()
+	}
+	if lvl, has := levelForIP(cfg.cidrLevels, ip); has {
+		return lvl
+	}
+	if lvl, has := fl.paths[route]; has {
+		return lvl
 	}
 	if lvl, has := cfg.pathLevels[route]; has {
 		return lvl
 	}
-	return cfg.defaultLevel
+	if lvl, has := fl.routes[c.FullPath()]; has {
+		return lvl
+	}
+	if lvl, has := cfg.routeLevels[c.FullPath()]; has {
+		return lvl
+	}
+	if c.Request != nil {
+		if lvl, has := cfg.methodLevels[c.Request.Method]; has {
+			return lvl
+		}
+	}
+	return cfg.defaultLeveler.Level()
 }
 
 /*
@@ -266,3 +1480,44 @@ Returns:
 func Get(c *gin.Context) *slog.Logger {
 	return c.MustGet(loggerKey).(*slog.Logger)
 }
+
+/*
+TryGet retrieves the *slog.Logger instance from the given gin.Context.
+It reports whether the logger was set by the middleware, and never panics.
+
+Parameters:
+
+	c - the gin.Context from which to retrieve the logger.
+
+Returns:
+
+	*slog.Logger - the logger instance stored in the context, or nil.
+	bool - true if the logger was found.
+*/
+func TryGet(c *gin.Context) (*slog.Logger, bool) {
+	if c == nil {
+		return nil, false
+	}
+	v, _ := c.Get(loggerKey)
+	l, ok := v.(*slog.Logger)
+	return l, ok
+}
+
+/*
+GetOrDefault retrieves the *slog.Logger instance from the given gin.Context,
+falling back to slog.Default() when the middleware is not installed.
+
+Parameters:
+
+	c - the gin.Context from which to retrieve the logger.
+
+Returns:
+
+	*slog.Logger - the logger instance stored in the context, or slog.Default().
+*/
+func GetOrDefault(c *gin.Context) *slog.Logger {
+	if l, ok := TryGet(c); ok {
+		return l
+	}
+	return slog.Default()
+}
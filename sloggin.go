@@ -0,0 +1,38 @@
+package slog
+
+import "log/slog"
+
+// slogGinFieldNames logs the request ID under samber/slog-gin's id key.
+var slogGinFieldNames = FieldNames{
+	FieldRequestID: "id",
+}
+
+// slogGinLayout renders the request like samber/slog-gin: a request group with
+// the request start time and a response group with its end time, latency and
+// status. host is only known with WithConnectionInfo.
+func slogGinLayout(rec *AccessRecord) []slog.Attr {
+	req := []slog.Attr{
+		slog.Time("time", rec.Time.Add(-rec.Latency).UTC()),
+		slog.String("method", rec.Method),
+	}
+	if rec.Host != "" {
+		req = append(req, slog.String("host", rec.Host))
+	}
+	req = append(req,
+		slog.String("path", rec.Path),
+		slog.String("query", rec.Query),
+		slog.String("route", rec.Route),
+		slog.String("ip", rec.IP),
+		slog.String("referer", rec.Referer),
+		slog.String("user-agent", rec.UserAgent),
+	)
+	return []slog.Attr{
+		{Key: "request", Value: slog.GroupValue(req...)},
+		slog.Group("response",
+			slog.Time("time", rec.Time.UTC()),
+			slog.Duration("latency", rec.Latency),
+			slog.Int("status", rec.Status),
+			slog.Int("length", rec.BodySize),
+		),
+	}
+}
@@ -0,0 +1,193 @@
+/*
+Package slogtest helps testing code that logs through the gin slog middleware.
+Handler captures records in memory, NewEngine returns a gin engine wired to
+one, and Handler.RequireLogged asserts an access log record was written:
+
+	r, logs := slogtest.NewEngine()
+	r.GET("/ping", handler)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/ping", nil))
+	logs.RequireLogged(t, http.MethodGet, "/ping", http.StatusOK)
+*/
+package slogtest
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	ginslog "github.com/gin-contrib/slog"
+	"github.com/gin-gonic/gin"
+)
+
+// Entry is a captured record with its attributes, including those of the
+// logger, keyed by their dotted group path.
+type Entry struct {
+	Time    time.Time
+	Level   slog.Level
+	Message string
+	Attrs   map[string]slog.Value
+}
+
+// Attr returns the value of the attribute at key, e.g. "status" or "http.status".
+func (e Entry) Attr(key string) (slog.Value, bool) {
+	v, ok := e.Attrs[key]
+	return v, ok
+}
+
+// store holds the entries shared by a handler and its derived handlers.
+type store struct {
+	mu      sync.Mutex
+	entries []Entry
+}
+
+// Handler is a slog.Handler capturing records in memory, at every level.
+type Handler struct {
+	store  *store
+	attrs  []slog.Attr
+	groups []string
+}
+
+// NewHandler returns an empty capturing handler.
+func NewHandler() *Handler {
+	return &Handler{store: &store{}}
+}
+
+// Enabled implements slog.Handler.
+func (h *Handler) Enabled(context.Context, slog.Level) bool {
+	return true
+}
+
+// Handle implements slog.Handler.
+func (h *Handler) Handle(_ context.Context, r slog.Record) error {
+	e := Entry{Time: r.Time, Level: r.Level, Message: r.Message, Attrs: map[string]slog.Value{}}
+	for _, a := range h.attrs {
+		addAttr(e.Attrs, "", a)
+	}
+	prefix := strings.Join(h.groups, ".")
+	if prefix != "" {
+		prefix += "."
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addAttr(e.Attrs, prefix, a)
+		return true
+	})
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.entries = append(h.store.entries, e)
+	return nil
+}
+
+// addAttr adds a to attrs, flattening groups into dotted keys.
+func addAttr(attrs map[string]slog.Value, prefix string, a slog.Attr) {
+	v := a.Value.Resolve()
+	if v.Kind() != slog.KindGroup {
+		attrs[prefix+a.Key] = v
+		return
+	}
+	if a.Key != "" {
+		prefix += a.Key + "."
+	}
+	for _, ga := range v.Group() {
+		addAttr(attrs, prefix, ga)
+	}
+}
+
+// WithAttrs implements slog.Handler.
+func (h *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = slices.Clone(h.attrs)
+	prefix := strings.Join(h.groups, ".")
+	for _, a := range attrs {
+		if prefix != "" {
+			a = slog.Attr{Key: prefix, Value: slog.GroupValue(a)}
+		}
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.groups = append(slices.Clip(h.groups), name)
+	return &h2
+}
+
+// Entries returns the captured entries, oldest first.
+func (h *Handler) Entries() []Entry {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return slices.Clone(h.store.entries)
+}
+
+// Len returns the number of captured entries.
+func (h *Handler) Len() int {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	return len(h.store.entries)
+}
+
+// Reset discards the captured entries.
+func (h *Handler) Reset() {
+	h.store.mu.Lock()
+	defer h.store.mu.Unlock()
+	h.store.entries = nil
+}
+
+// Find returns the first captured entry match reports true for.
+func (h *Handler) Find(match func(Entry) bool) (Entry, bool) {
+	for _, e := range h.Entries() {
+		if match(e) {
+			return e, true
+		}
+	}
+	return Entry{}, false
+}
+
+// Logged returns the access log entry of a request with the given method, path
+// and status, looked up under the default field names.
+func (h *Handler) Logged(method, path string, status int) (Entry, bool) {
+	return h.Find(func(e Entry) bool {
+		m, _ := e.Attr(string(ginslog.FieldMethod))
+		p, _ := e.Attr(string(ginslog.FieldPath))
+		s, _ := e.Attr(string(ginslog.FieldStatus))
+		return m.String() == method && p.String() == path && s.Kind() == slog.KindInt64 && s.Int64() == int64(status)
+	})
+}
+
+// RequireLogged fails the test unless an access log entry of a request with
+// the given method, path and status was captured.
+func (h *Handler) RequireLogged(t testing.TB, method, path string, status int) Entry {
+	t.Helper()
+	e, ok := h.Logged(method, path, status)
+	if !ok {
+		var got []string
+		for _, e := range h.Entries() {
+			got = append(got, e.Message+" "+attrString(e, ginslog.FieldMethod)+" "+
+				attrString(e, ginslog.FieldPath)+" "+attrString(e, ginslog.FieldStatus))
+		}
+		t.Fatalf("no access log entry for %s %s %d, got:\n%s", method, path, status, strings.Join(got, "\n"))
+	}
+	return e
+}
+
+func attrString(e Entry, f ginslog.Field) string {
+	v, _ := e.Attr(string(f))
+	return v.String()
+}
+
+// NewEngine returns a gin engine using the middleware with opts, logging to a
+// new capturing handler.
+func NewEngine(opts ...ginslog.Option) (*gin.Engine, *Handler) {
+	h := NewHandler()
+	r := gin.New()
+	r.Use(ginslog.SetLogger(append([]ginslog.Option{ginslog.WithHandler(h)}, opts...)...))
+	return r, h
+}
@@ -0,0 +1,95 @@
+package slog
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxStackDepth bounds the frames recorded by ErrorWithStack.
+const maxStackDepth = 32
+
+// stackError is an error carrying the stack of its ErrorWithStack call.
+type stackError struct {
+	error
+	pcs []uintptr
+}
+
+// Unwrap returns the wrapped error.
+func (e *stackError) Unwrap() error {
+	return e.error
+}
+
+// StackTrace returns the program counters of the recorded stack.
+func (e *stackError) StackTrace() []uintptr {
+	return e.pcs
+}
+
+/*
+ErrorWithStack attaches err to the context like c.Error, recording the
+caller's stack so that WithErrorStackTrace can log it on 5xx responses.
+
+Parameters:
+
+	c - the gin.Context of the request.
+	err - the error to attach.
+
+Returns:
+
+	*gin.Error - the attached error, as returned by c.Error.
+*/
+func ErrorWithStack(c *gin.Context, err error) *gin.Error {
+	pcs := make([]uintptr, maxStackDepth)
+	n := runtime.Callers(2, pcs)
+	return c.Error(&stackError{error: err, pcs: pcs[:n]})
+}
+
+// stackFrame is a logged stack frame.
+type stackFrame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// errorStack returns the frames of the first error in err's Unwrap chain
+// with a StackTrace method, either ErrorWithStack's or one returning a slice
+// of program counters like github.com/pkg/errors.
+func errorStack(err error) []stackFrame {
+	for err != nil {
+		if pcs := stackTrace(err); len(pcs) > 0 {
+			frames := runtime.CallersFrames(pcs)
+			var out []stackFrame
+			for {
+				f, more := frames.Next()
+				out = append(out, stackFrame{Function: f.Function, File: f.File, Line: f.Line})
+				if !more {
+					return out
+				}
+			}
+		}
+		err = errors.Unwrap(err)
+	}
+	return nil
+}
+
+// stackTrace returns the program counters of err's own StackTrace method.
+func stackTrace(err error) []uintptr {
+	if st, ok := err.(interface{ StackTrace() []uintptr }); ok {
+		return st.StackTrace()
+	}
+	m := reflect.ValueOf(err).MethodByName("StackTrace")
+	if !m.IsValid() || m.Type().NumIn() != 0 || m.Type().NumOut() != 1 {
+		return nil
+	}
+	if t := m.Type().Out(0); t.Kind() != reflect.Slice || t.Elem().Kind() != reflect.Uintptr {
+		return nil
+	}
+	st := m.Call(nil)[0]
+	pcs := make([]uintptr, st.Len())
+	for i := range pcs {
+		pcs[i] = uintptr(st.Index(i).Uint())
+	}
+	return pcs
+}
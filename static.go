@@ -0,0 +1,55 @@
+package slog
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// staticHandlerNames identifies the handlers gin registers for Static, StaticFS,
+// StaticFile and StaticFileFS routes.
+var staticHandlerNames = []string{
+	"github.com/gin-gonic/gin.(*RouterGroup).createStaticHandler.",
+	"github.com/gin-gonic/gin.(*RouterGroup).StaticFile.",
+	"github.com/gin-gonic/gin.(*RouterGroup).StaticFileFS.",
+}
+
+func isStaticHandler(name string) bool {
+	for _, prefix := range staticHandlerNames {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+/*
+AutoSkipStatic skips logging for requests served by the Static, StaticFS,
+StaticFile and StaticFileFS routes registered on engine. Routes are inspected
+on the first request, so they may be registered after the middleware:
+
+	r.Use(slog.SetLogger(slog.AutoSkipStatic(r)))
+	r.Static("/assets", "./public")
+*/
+func AutoSkipStatic(engine *gin.Engine) Option {
+	var (
+		once   sync.Once
+		routes map[string]struct{}
+	)
+	skip := func(c *gin.Context) bool {
+		once.Do(func() {
+			routes = map[string]struct{}{}
+			for _, r := range engine.Routes() {
+				if isStaticHandler(r.Handler) {
+					routes[r.Path] = struct{}{}
+				}
+			}
+		})
+		_, ok := routes[c.FullPath()]
+		return ok
+	}
+	return optionFunc(func(c *config) {
+		c.skippers = append(c.skippers, skip)
+	})
+}
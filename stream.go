@@ -0,0 +1,44 @@
+package slog
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// streamWriter records when the first byte of the response is written, so
+// streamed responses (e.g. SSE) report their start separately from their length.
+type streamWriter struct {
+	gin.ResponseWriter
+	first time.Time
+}
+
+func (w *streamWriter) mark() {
+	if w.first.IsZero() {
+		w.first = time.Now()
+	}
+}
+
+// Write implements io.Writer.
+func (w *streamWriter) Write(b []byte) (int, error) {
+	w.mark()
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString implements io.StringWriter.
+func (w *streamWriter) WriteString(s string) (int, error) {
+	w.mark()
+	return w.ResponseWriter.WriteString(s)
+}
+
+// WriteHeaderNow implements gin.ResponseWriter.
+func (w *streamWriter) WriteHeaderNow() {
+	w.mark()
+	w.ResponseWriter.WriteHeaderNow()
+}
+
+// Flush implements http.Flusher.
+func (w *streamWriter) Flush() {
+	w.mark()
+	w.ResponseWriter.Flush()
+}
@@ -0,0 +1,37 @@
+package slog
+
+import (
+	"net"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TenantFromHeader returns a WithTenant function reading the tenant from the
+// given request header, e.g. X-Tenant-ID.
+func TenantFromHeader(header string) func(*gin.Context) string {
+	return func(c *gin.Context) string {
+		return c.GetHeader(header)
+	}
+}
+
+// TenantFromSubdomain returns a WithTenant function reading the tenant from the
+// subdomain of the Host under domain, so acme.example.com yields acme for
+// example.com. Other hosts have no tenant.
+func TenantFromSubdomain(domain string) func(*gin.Context) string {
+	suffix := "." + strings.ToLower(strings.Trim(domain, "."))
+	return func(c *gin.Context) string {
+		host := c.Request.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		sub, ok := strings.CutSuffix(strings.ToLower(host), suffix)
+		if !ok || sub == "" {
+			return ""
+		}
+		if i := strings.LastIndexByte(sub, '.'); i >= 0 {
+			sub = sub[i+1:]
+		}
+		return sub
+	}
+}
@@ -0,0 +1,27 @@
+package slog
+
+import (
+	"context"
+	"log/slog"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// spanIDs returns the trace and span ids of the OpenTelemetry span carried by
+// ctx. It reports false when there is no valid span context.
+func spanIDs(ctx context.Context) (traceID, spanID string, ok bool) {
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.IsValid() {
+		return "", "", false
+	}
+	return sc.TraceID().String(), sc.SpanID().String(), true
+}
+
+// traceAttrs returns the trace_id and, when set, span_id attributes.
+func traceAttrs(names FieldNames, traceID, spanID string) []any {
+	attrs := []any{slog.String(names.key(FieldTraceID), traceID)}
+	if spanID != "" {
+		attrs = append(attrs, slog.String(names.key(FieldSpanID), spanID))
+	}
+	return attrs
+}
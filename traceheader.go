@@ -0,0 +1,117 @@
+package slog
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TraceFormat is a trace context propagation header format.
+type TraceFormat int
+
+const (
+	// TraceFormatW3C is the W3C Trace Context traceparent header.
+	TraceFormatW3C TraceFormat = iota
+	// TraceFormatB3 is the Zipkin b3 single header or X-B3-TraceId/X-B3-SpanId headers.
+	TraceFormatB3
+	// TraceFormatCloudTrace is the Google X-Cloud-Trace-Context header.
+	TraceFormatCloudTrace
+)
+
+// traceHeader is a trace context parsed from a request header.
+type traceHeader struct {
+	traceID string
+	spanID  string
+	headers map[string]string // headers to propagate to the response
+}
+
+// parseTraceHeaders returns the trace context of the first recognized format.
+func parseTraceHeaders(c *gin.Context, formats []TraceFormat) (traceHeader, bool) {
+	for _, f := range formats {
+		var th traceHeader
+		var ok bool
+		switch f {
+		case TraceFormatW3C:
+			th, ok = parseTraceparent(c.GetHeader("traceparent"))
+		case TraceFormatB3:
+			th, ok = parseB3(c)
+		case TraceFormatCloudTrace:
+			th, ok = parseCloudTrace(c.GetHeader("X-Cloud-Trace-Context"))
+		}
+		if ok {
+			return th, true
+		}
+	}
+	return traceHeader{}, false
+}
+
+// parseTraceparent parses "version-traceid-parentid-flags".
+func parseTraceparent(v string) (traceHeader, bool) {
+	parts := strings.Split(strings.TrimSpace(v), "-")
+	if len(parts) < 4 || len(parts[0]) != 2 || parts[0] == "ff" ||
+		!isHexID(parts[1], 32) || !isHexID(parts[2], 16) {
+		return traceHeader{}, false
+	}
+	return traceHeader{
+		traceID: parts[1],
+		spanID:  parts[2],
+		headers: map[string]string{"traceparent": v},
+	}, true
+}
+
+// parseB3 parses the b3 single header, falling back to the multi-header form.
+func parseB3(c *gin.Context) (traceHeader, bool) {
+	if v := c.GetHeader("b3"); v != "" {
+		parts := strings.Split(strings.TrimSpace(v), "-")
+		if len(parts) >= 2 && (isHexID(parts[0], 32) || isHexID(parts[0], 16)) && isHexID(parts[1], 16) {
+			return traceHeader{traceID: parts[0], spanID: parts[1], headers: map[string]string{"b3": v}}, true
+		}
+		return traceHeader{}, false
+	}
+	traceID, spanID := c.GetHeader("X-B3-TraceId"), c.GetHeader("X-B3-SpanId")
+	if (!isHexID(traceID, 32) && !isHexID(traceID, 16)) || !isHexID(spanID, 16) {
+		return traceHeader{}, false
+	}
+	return traceHeader{
+		traceID: traceID,
+		spanID:  spanID,
+		headers: map[string]string{"X-B3-TraceId": traceID, "X-B3-SpanId": spanID},
+	}, true
+}
+
+// parseCloudTrace parses "TRACE_ID/SPAN_ID;o=OPTIONS".
+func parseCloudTrace(v string) (traceHeader, bool) {
+	ids, _, _ := strings.Cut(strings.TrimSpace(v), ";")
+	traceID, spanID, _ := strings.Cut(ids, "/")
+	if !isHexID(traceID, 32) {
+		return traceHeader{}, false
+	}
+	for _, r := range spanID {
+		if r < '0' || r > '9' {
+			return traceHeader{}, false
+		}
+	}
+	return traceHeader{
+		traceID: traceID,
+		spanID:  spanID,
+		headers: map[string]string{"X-Cloud-Trace-Context": v},
+	}, true
+}
+
+// isHexID reports whether s is a non-zero lower-case hex id of length n.
+func isHexID(s string, n int) bool {
+	if len(s) != n {
+		return false
+	}
+	zero := true
+	for _, r := range s {
+		switch {
+		case r == '0':
+		case r >= '1' && r <= '9', r >= 'a' && r <= 'f':
+			zero = false
+		default:
+			return false
+		}
+	}
+	return !zero
+}
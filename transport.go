@@ -0,0 +1,65 @@
+package slog
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// transport is an http.RoundTripper logging outbound requests.
+type transport struct {
+	base http.RoundTripper
+}
+
+/*
+NewTransport returns an http.RoundTripper that logs each outbound request
+with the request-scoped logger found in the request's context (see
+FromContext), so outbound lines carry the same correlation attributes as the
+inbound request. A nil base uses http.DefaultTransport.
+
+	client := &http.Client{Transport: slog.NewTransport(nil)}
+	req, _ := http.NewRequestWithContext(c.Request.Context(), http.MethodGet, url, nil)
+	resp, err := client.Do(req)
+
+The query string is not logged since it often carries credentials.
+*/
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &transport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	latency := time.Since(start)
+
+	ctx := req.Context()
+	l := FromContext(ctx)
+	target := req.URL.Scheme + "://" + req.URL.Host + req.URL.EscapedPath()
+	if err != nil {
+		l.LogAttrs(ctx, slog.LevelError, "Outbound request failed",
+			slog.String("method", req.Method),
+			slog.String("url", target),
+			slog.Duration("latency", latency),
+			slog.String("error", err.Error()),
+		)
+		return resp, err
+	}
+
+	level := slog.LevelInfo
+	if resp.StatusCode >= http.StatusInternalServerError {
+		level = slog.LevelError
+	} else if resp.StatusCode >= http.StatusBadRequest {
+		level = slog.LevelWarn
+	}
+	l.LogAttrs(ctx, level, "Outbound request",
+		slog.String("method", req.Method),
+		slog.String("url", target),
+		slog.Int("status", resp.StatusCode),
+		slog.Duration("latency", latency),
+	)
+	return resp, nil
+}
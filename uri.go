@@ -0,0 +1,36 @@
+package slog
+
+import (
+	"net/url"
+	"strings"
+)
+
+// redactedValue replaces the values of redacted query parameters.
+const redactedValue = "REDACTED"
+
+// redactQuery replaces the values of hidden parameters in a raw query string,
+// preserving the order and encoding of every other parameter.
+func redactQuery(rawQuery string, hidden map[string]struct{}) string {
+	if rawQuery == "" || len(hidden) == 0 {
+		return rawQuery
+	}
+	pairs := strings.Split(rawQuery, "&")
+	for i, pair := range pairs {
+		key, _, hasValue := strings.Cut(pair, "=")
+		name, err := url.QueryUnescape(key)
+		if err != nil {
+			name = key
+		}
+		if _, ok := hidden[strings.ToLower(name)]; ok && hasValue {
+			pairs[i] = key + "=" + redactedValue
+		}
+	}
+	return strings.Join(pairs, "&")
+}
+
+// requestURI reconstructs the escaped request target with a redacted query
+// and without a fragment.
+func requestURI(u *url.URL, hidden map[string]struct{}) string {
+	target := url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: redactQuery(u.RawQuery, hidden)}
+	return target.RequestURI()
+}
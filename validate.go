@@ -0,0 +1,53 @@
+package slog
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// validate reports the invalid or contradictory settings of the assembled
+// configuration, all of them at once.
+func (cfg *config) validate() error {
+	var errs []error
+	if cfg.output == nil && cfg.handler == nil && cfg.baseLogger == nil && cfg.syslog == nil {
+		errs = append(errs, errors.New("nil writer"))
+	}
+	if cfg.sampleRate < 0 || cfg.sampleRate > 1 {
+		errs = append(errs, fmt.Errorf("sample rate %v is not between 0 and 1", cfg.sampleRate))
+	}
+	for _, rule := range cfg.sampleRules {
+		if rule.rate < 0 || rule.rate > 1 {
+			errs = append(errs, fmt.Errorf("sample rate %v for %s is not between 0 and 1", rule.rate, rule.re))
+		}
+	}
+	for _, route := range slices.Sorted(maps.Keys(cfg.pathSampleRates)) {
+		if rate := cfg.pathSampleRates[route]; rate < 0 || rate > 1 {
+			errs = append(errs, fmt.Errorf("sample rate %v for %s is not between 0 and 1", rate, route))
+		}
+	}
+	if cfg.bodyDigest != "" && cfg.bodyDigest.new() == nil {
+		errs = append(errs, fmt.Errorf("unsupported body digest algorithm %q", cfg.bodyDigest))
+	}
+	for _, status := range slices.Sorted(maps.Keys(cfg.specificLevelByStatusCode)) {
+		if status < 100 || status > 599 {
+			errs = append(errs, fmt.Errorf("invalid status code %d in status levels", status))
+		}
+	}
+	for _, p := range slices.Sorted(maps.Keys(cfg.pathLevels)) {
+		if !strings.HasPrefix(p, "/") {
+			errs = append(errs, fmt.Errorf("path level %q does not start with /", p))
+		}
+		if slices.Contains(cfg.skipPath, p) || skipPathRegexp(cfg, p) {
+			errs = append(errs, fmt.Errorf("path %s is both skipped and given a level", p))
+		}
+	}
+	for _, route := range slices.Sorted(maps.Keys(cfg.routeLevels)) {
+		if !strings.HasPrefix(route, "/") {
+			errs = append(errs, fmt.Errorf("route level %q does not start with /", route))
+		}
+	}
+	return errors.Join(errs...)
+}
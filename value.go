@@ -0,0 +1,57 @@
+package slog
+
+import (
+	"log/slog"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestIDHeader is the conventional header carrying a request identifier.
+const requestIDHeader = "X-Request-ID"
+
+// requestValue lazily renders a summary of a request.
+type requestValue struct {
+	c *gin.Context
+}
+
+/*
+RequestValue returns a slog.LogValuer that renders a consistent summary of the
+request (method, route, ip and request_id) when the record is handled, e.g.
+
+	logger.Info("payment failed", "request", slog.RequestValue(c))
+
+The ip is the one the middleware serving the request logs, resolved with
+WithTrustedProxies or WithClientIPResolver, anonymized and hashed as configured.
+*/
+func RequestValue(c *gin.Context) slog.LogValuer {
+	return requestValue{c: c}
+}
+
+// LogValue implements slog.LogValuer.
+func (v requestValue) LogValue() slog.Value {
+	if v.c == nil || v.c.Request == nil {
+		return slog.GroupValue()
+	}
+	attrs := []slog.Attr{
+		slog.String("method", v.c.Request.Method),
+		slog.String("route", v.c.FullPath()),
+		slog.String("ip", v.ip()),
+	}
+	id := RequestID(v.c)
+	if id == "" {
+		id = v.c.GetHeader(requestIDHeader)
+	}
+	if id != "" {
+		attrs = append(attrs, slog.String("request_id", id))
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// ip returns the client IP as logged by the middleware serving the request,
+// or c.ClientIP() outside one.
+func (v requestValue) ip() string {
+	if cfg, ok := v.c.Value(configKey).(*config); ok {
+		return cfg.loggedIP(clientIP(cfg, v.c))
+	}
+	return v.c.ClientIP()
+}
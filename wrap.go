@@ -0,0 +1,88 @@
+package slog
+
+import (
+	"context"
+	"net/http"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+/*
+Wrap returns an http.Handler that logs requests served by h with the same
+configuration and attributes as SetLogger, so handlers mounted outside gin
+(pprof, promhttp, ...) get identical access logs. The route attribute is empty
+as there is no gin route; use WithRouteNormalizer to set one. For the same
+reason, options keyed by route template (WithRouteLevel, WithRouteOptions,
+WithRouteMeta, WithRouteLoggers, ...) do not apply, and WithNotFoundSummary
+is ignored: the 404 responses of h are logged as they are.
+
+	mux.Handle("/metrics", slog.Wrap(promhttp.Handler(), slog.WithDefaultLevel(slog.LevelDebug)))
+
+Wrap panics if the configuration is invalid, like SetLogger.
+*/
+func Wrap(h http.Handler, opts ...Option) http.Handler {
+	return &wrapped{logger: wrapLogger(opts), h: h}
+}
+
+/*
+NewHTTPMiddleware returns a net/http middleware, e.g. for chi's Use, logging
+like Wrap. The handlers it wraps share one configuration, so sampling, rate
+limits and metrics apply across all of them as with a single SetLogger.
+
+	r := chi.NewRouter()
+	r.Use(slog.NewHTTPMiddleware(slog.WithRequestID("X-Request-ID")))
+
+NewHTTPMiddleware panics if the configuration is invalid, like SetLogger.
+*/
+func NewHTTPMiddleware(opts ...Option) func(http.Handler) http.Handler {
+	logger := wrapLogger(opts)
+	return func(h http.Handler) http.Handler {
+		return &wrapped{logger: logger, h: h}
+	}
+}
+
+// wrapLogger returns the logger middleware of wrapped handlers.
+func wrapLogger(opts []Option) gin.HandlerFunc {
+	cfg := newConfig(opts...)
+	// Wrapped handlers have no gin routes, so every request would count as not found.
+	cfg.notFoundInterval = 0
+	if err := cfg.init(); err != nil {
+		panic("slog: " + err.Error())
+	}
+	return cfg.handle
+}
+
+// wrappedKey is the request context key of the wrapped handler being served.
+type wrappedKey struct{}
+
+// wrapped serves h behind the logger middleware.
+type wrapped struct {
+	logger gin.HandlerFunc
+	h      http.Handler
+}
+
+// ServeHTTP implements http.Handler.
+func (w *wrapped) ServeHTTP(rw http.ResponseWriter, r *http.Request) {
+	wrapEngine().ServeHTTP(rw, r.WithContext(context.WithValue(r.Context(), wrappedKey{}, w)))
+}
+
+/*
+wrapEngine is the gin engine shared by the wrapped handlers, built once so it
+prints gin's debug warnings once. It has no routes: requests go through
+NoRoute, which runs the logger and handler of the wrapped handler carried by
+the request context.
+*/
+var wrapEngine = sync.OnceValue(func() *gin.Engine {
+	engine := gin.New()
+	engine.RedirectTrailingSlash = false
+	engine.RedirectFixedPath = false
+	engine.NoRoute(func(c *gin.Context) {
+		c.Request.Context().Value(wrappedKey{}).(*wrapped).logger(c)
+	}, func(c *gin.Context) {
+		// NoRoute handlers start with a 404 status; let h decide.
+		c.Status(http.StatusOK)
+		c.Request.Context().Value(wrappedKey{}).(*wrapped).h.ServeHTTP(c.Writer, c.Request)
+	})
+	return engine
+})
@@ -0,0 +1,38 @@
+package slog
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// responseWriter wraps gin.ResponseWriter to observe the response as it is written.
+type responseWriter struct {
+	gin.ResponseWriter
+	bodyMax     int          // max captured bytes of the response body
+	successBody bool         // capture bodies of successful responses too
+	body        bytes.Buffer // captured response body
+}
+
+// Write implements io.Writer.
+func (w *responseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.observe(b[:n])
+	return n, err
+}
+
+// WriteString implements io.StringWriter.
+func (w *responseWriter) WriteString(s string) (int, error) {
+	n, err := w.ResponseWriter.WriteString(s)
+	w.observe([]byte(s[:n]))
+	return n, err
+}
+
+func (w *responseWriter) observe(b []byte) {
+	if w.bodyMax > 0 && (w.successBody || w.Status() >= http.StatusBadRequest) {
+		if rem := w.bodyMax - w.body.Len(); rem > 0 {
+			w.body.Write(b[:min(len(b), rem)])
+		}
+	}
+}